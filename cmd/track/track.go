@@ -29,5 +29,5 @@ func init() {
 		Use:   "track",
 		Short: "Record resource references for tracking purposes",
 	}
-	TrackCmd.AddCommand(trackBundleCmd(tracker.Track, tracker.PullImage, tracker.PushImage))
+	TrackCmd.AddCommand(trackBundleCmd(tracker.Track, tracker.PullImage, tracker.PushImage, tracker.Discover))
 }
@@ -18,29 +18,53 @@ package track
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	hd "github.com/MakeNowJust/heredoc"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+	"github.com/enterprise-contract/ec-cli/internal/tracker"
 	"github.com/enterprise-contract/ec-cli/internal/utils"
 )
 
-type trackBundleFn func(context.Context, []string, []byte, bool) ([]byte, error)
-type pullImageFn func(context.Context, string) ([]byte, error)
-type pushImageFn func(context.Context, string, []byte, string) error
+type trackBundleFn func(context.Context, []string, []byte, bool, tracker.EffectiveOn, string) ([]byte, error)
+type pullImageFn func(context.Context, string, *cosign.CheckOpts) ([]byte, error)
+type pushImageFn func(context.Context, string, []byte, string, tracker.SignOptions) error
+type discoverFn func(context.Context, []string, string, []byte) ([]string, error)
 
-func trackBundleCmd(track trackBundleFn, pullImage pullImageFn, pushImage pushImageFn) *cobra.Command {
+func trackBundleCmd(track trackBundleFn, pullImage pullImageFn, pushImage pushImageFn, discover discoverFn) *cobra.Command {
 	var params = struct {
-		bundles []string
-		input   string
-		prune   bool
-		replace bool
-		output  string
+		bundles     []string
+		input       string
+		prune       bool
+		replace     bool
+		output      string
+		effectiveOn string
+		collection  string
+		repos       []string
+		tagRegex    string
+		urls        []string
+		effective   tracker.EffectiveOn
+		publicKey   string
+		rekorURL    string
+		identity    cosign.Identity
+		skipVerify  bool
+		checkOpts   *cosign.CheckOpts
+		signing     tracker.SignOptions
 	}{
-		prune: true,
+		prune:       true,
+		effectiveOn: "30d",
+		signing: tracker.SignOptions{
+			FulcioURL: options.DefaultFulcioURL,
+		},
 	}
 
 	cmd := &cobra.Command{
@@ -61,13 +85,41 @@ func trackBundleCmd(track trackBundleFn, pullImage pullImageFn, pushImage pushIm
 
 			The output is meant to assist enforcement of policies that ensure the
 			most recent Tekton Bundle is used. As such, each entry contains an
-			"effective_on" date which is set to 30 days from today. This indicates
-			the Tekton Bundle usage should be updated within that period.
+			"effective_on" date which is set to 30 days from today, or as set by
+			--effective-on. This indicates the Tekton Bundle usage should be updated
+			within that period. The effective_on date of a single Tekton Bundle can
+			be set by appending it to the bundle reference, e.g.
+			--bundle <IMAGE>=7d.
+
+			Other OCI artifacts, e.g. base images, builder images or policy bundles,
+			can be tracked in a collection of any name given by --collection. All the
+			given images are then tracked only in that collection, regardless of their
+			content.
+
+			Instead of, or in addition to, listing each image with --bundle, the
+			images can be discovered from a repository given by --repository. The
+			images with a tag matching --tag-regex, if set, that are not tracked yet
+			are tracked. Images that record a creation time older than the latest
+			image of the repository already tracked are skipped.
 
 			If --prune is set, on by default, non-acceptable entries are removed.
 			Any entry with an effective_on date in the future, and the entry with
 			the most recent effective_on date *not* in the future are considered
 			acceptable.
+
+			The tracking data pushed to an image registry can be signed, either with
+			the key given by --signing-key, or with --keyless using an ephemeral key
+			certified by Fulcio and recorded in the Rekor transparency log given by
+			--rekor-url. The signature of the tracking data read from an image
+			registry, given by --input oci:..., is verified with --public-key, or
+			for keyless signatures, with --certificate-identity and
+			--certificate-oidc-issuer (or their regular expression variants). One
+			of them is required unless --insecure-skip-verify is set.
+
+			NOTE: The tracking data read from an image registry used to be trusted
+			without any verification. Existing invocations using --input oci:...
+			now fail unless the signature is verified, or --insecure-skip-verify is
+			set to keep the previous behavior.
 		`),
 
 		Example: hd.Doc(`
@@ -93,7 +145,43 @@ func trackBundleCmd(track trackBundleFn, pullImage pullImageFn, pushImage pushIm
 
 			Extend an existing tracking image with a new bundle and push to an image registry:
 
-			  ec track bundle --bundle <IMAGE1> --input <oci:registry.io/repository/image:tag> --replace
+			  ec track bundle --bundle <IMAGE1> --input <oci:registry.io/repository/image:tag> --replace \
+			    --public-key <path/to/public/key>
+
+			Give 7 days to update to the new bundles:
+
+			  ec track bundle --bundle <IMAGE1> --bundle <IMAGE2> --effective-on 7d
+
+			Give 7 days to update to a bundle with a security fix, and 60 days for another:
+
+			  ec track bundle --bundle <IMAGE1>=7d --bundle <IMAGE2> --effective-on 60d
+
+			Make the new bundle effective on a specific date:
+
+			  ec track bundle --bundle <IMAGE1> --effective-on 2023-06-01
+
+			Track acceptable base images in the "base-images" collection:
+
+			  ec track bundle --bundle <IMAGE1> --collection base-images --input <path/to/input/file> --replace
+
+			Track the new bundles of a repository, with a tag like v1.2, not yet in an
+			existing tracking image:
+
+			  ec track bundle --repository <REPOSITORY> --tag-regex 'v\d+\.\d+' --input <oci:registry.io/repository/image:tag> --replace \
+			    --public-key <path/to/public/key>
+
+			Verify the existing tracking image and sign the extended one with a key:
+
+			  ec track bundle --bundle <IMAGE1> --input <oci:registry.io/repository/image:tag> --replace \
+			    --public-key <path/to/public/key> --signing-key <path/to/private/key>
+
+			Verify the existing tracking image and sign the extended one keyless, e.g. from a
+			GitHub workflow:
+
+			  ec track bundle --bundle <IMAGE1> --input <oci:registry.io/repository/image:tag> --replace \
+			    --keyless --identity-token <TOKEN> --rekor-url 'https://rekor.sigstore.dev' \
+			    --certificate-identity 'https://github.com/user/repo/.github/workflows/track.yaml@refs/heads/main' \
+			    --certificate-oidc-issuer 'https://token.actions.githubusercontent.com'
 
 			Skip pruning for unacceptable entries:
 
@@ -101,6 +189,73 @@ func trackBundleCmd(track trackBundleFn, pullImage pullImageFn, pushImage pushIm
 		`),
 
 		Args: cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(params.bundles) == 0 && len(params.repos) == 0 {
+				return errors.New("at least one --bundle or --repository is required")
+			}
+
+			effectiveOn, err := tracker.ParseEffectiveOn(params.effectiveOn)
+			if err != nil {
+				return err
+			}
+
+			params.effective = tracker.EffectiveOn{
+				Default:   effectiveOn,
+				Overrides: map[string]time.Time{},
+			}
+
+			// each bundle may override the effective on date, e.g.
+			// registry.io/repository/image:tag=7d
+			params.urls = make([]string, 0, len(params.bundles))
+			for _, bundle := range params.bundles {
+				url, value, found := strings.Cut(bundle, "=")
+				params.urls = append(params.urls, url)
+				if !found {
+					continue
+				}
+
+				effectiveOn, err := tracker.ParseEffectiveOn(value)
+				if err != nil {
+					return fmt.Errorf("bundle %s: %w", url, err)
+				}
+				params.effective.Overrides[url] = effectiveOn
+			}
+
+			pullsImage := strings.HasPrefix(params.input, "oci:")
+			pushesImage := strings.HasPrefix(params.output, "oci:") || (params.replace && pullsImage)
+
+			if params.signing.Enabled() && !pushesImage {
+				return errors.New("signing requires the tracking data to be written to an image registry, i.e. --output oci:... or --input oci:... with --replace")
+			}
+
+			params.signing.RekorURL = params.rekorURL
+			if params.signing.Keyless && params.signing.RekorURL == "" {
+				return errors.New("--keyless requires --rekor-url")
+			}
+
+			// the tracking data read from an image registry decides which
+			// images are acceptable, so its signature is verified unless
+			// explicitly skipped, using the same options as when validating
+			// images
+			verify := params.publicKey != "" || params.identity.Subject != "" || params.identity.SubjectRegExp != ""
+			if pullsImage && !verify && !params.skipVerify {
+				return errors.New("verifying the signature of the --input tracking image requires --public-key or --certificate-identity(-regexp), use --insecure-skip-verify to skip the verification")
+			}
+
+			if pullsImage && verify {
+				p, err := policy.NewPolicy(cmd.Context(), "", params.rekorURL, params.publicKey, policy.Now, params.identity)
+				if err != nil {
+					return err
+				}
+
+				if params.checkOpts, err = p.CheckOpts(); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			// capture the command and arguments so we can keep track of what
 			// Tekton bundles were used to getnerate the OPA/Conftest bundle
@@ -109,7 +264,7 @@ func trackBundleCmd(track trackBundleFn, pullImage pullImageFn, pushImage pushIm
 
 			var data []byte
 			if strings.HasPrefix(params.input, "oci:") {
-				data, err = pullImage(cmd.Context(), strings.TrimPrefix(params.input, "oci:"))
+				data, err = pullImage(cmd.Context(), strings.TrimPrefix(params.input, "oci:"), params.checkOpts)
 			} else if params.input != "" {
 				data, err = afero.ReadFile(fs, params.input)
 			}
@@ -117,7 +272,16 @@ func trackBundleCmd(track trackBundleFn, pullImage pullImageFn, pushImage pushIm
 				return err
 			}
 
-			out, err := track(cmd.Context(), params.bundles, data, params.prune)
+			urls := params.urls
+			if len(params.repos) > 0 {
+				discovered, err := discover(cmd.Context(), params.repos, params.tagRegex, data)
+				if err != nil {
+					return err
+				}
+				urls = append(urls, discovered...)
+			}
+
+			out, err := track(cmd.Context(), urls, data, params.prune, params.effective, params.collection)
 			if err != nil {
 				return err
 			}
@@ -126,7 +290,7 @@ func trackBundleCmd(track trackBundleFn, pullImage pullImageFn, pushImage pushIm
 			case params.output == "":
 				_, err = cmd.OutOrStdout().Write(out)
 			case strings.HasPrefix(params.output, "oci:"):
-				err = pushImage(cmd.Context(), strings.TrimPrefix(params.output, "oci:"), out, invocation)
+				err = pushImage(cmd.Context(), strings.TrimPrefix(params.output, "oci:"), out, invocation, params.signing)
 			default:
 				err = afero.WriteFile(fs, params.output, out, 0666)
 			}
@@ -137,7 +301,7 @@ func trackBundleCmd(track trackBundleFn, pullImage pullImageFn, pushImage pushIm
 
 			if params.replace && params.input != "" {
 				if strings.HasPrefix(params.input, "oci:") {
-					err = pushImage(cmd.Context(), strings.TrimPrefix(params.input, "oci:"), out, invocation)
+					err = pushImage(cmd.Context(), strings.TrimPrefix(params.input, "oci:"), out, invocation, params.signing)
 				} else {
 					var perm os.FileMode
 					if stat, err := fs.Stat(params.input); err != nil {
@@ -157,7 +321,26 @@ func trackBundleCmd(track trackBundleFn, pullImage pullImageFn, pushImage pushIm
 	cmd.Flags().StringVarP(&params.input, "input", "i", params.input, "existing tracking file")
 
 	cmd.Flags().StringSliceVarP(&params.bundles, "bundle", "b", params.bundles,
-		"bundle image reference to track - may be used multiple times (required)")
+		hd.Doc(`
+			bundle image reference to track, optionally followed by its effective on date,
+			e.g. <IMAGE>=7d - may be used multiple times. Required unless --repository is used`))
+
+	cmd.Flags().StringSliceVar(&params.repos, "repository", params.repos, hd.Doc(`
+		repository to discover the images to track from, i.e. the images not tracked yet
+		and newer than the latest tracked image, if known - may be used multiple times`))
+
+	cmd.Flags().StringVar(&params.tagRegex, "tag-regex", params.tagRegex, hd.Doc(`
+		regular expression the whole tag of the images discovered from --repository
+		must match, e.g. 'v\d+\.\d+'`))
+
+	cmd.Flags().StringVar(&params.effectiveOn, "effective-on", params.effectiveOn, hd.Doc(`
+		date from which the tracked bundles are effective, as a duration from today,
+		e.g. 7d or 168h, or as a date, e.g. 2023-06-01 or 2023-06-01T00:00:00Z`))
+
+	cmd.Flags().StringVar(&params.collection, "collection", params.collection, hd.Doc(`
+		name of the collection to track the images in, e.g. base-images. The images
+		can be any OCI artifact, by default Tekton bundles are tracked in the
+		pipeline-bundles and task-bundles collections based on their content`))
 
 	cmd.Flags().BoolVarP(&params.prune, "prune", "p", params.prune,
 		"remove entries that are no longer acceptable, i.e. a newer entry already effective exists")
@@ -167,9 +350,51 @@ func trackBundleCmd(track trackBundleFn, pullImage pullImageFn, pushImage pushIm
 	cmd.Flags().StringVarP(&params.output, "output", "o", params.output,
 		"write modified tracking file to a file. Use empty string for stdout, default behavior")
 
-	if err := cmd.MarkFlagRequired("bundle"); err != nil {
-		panic(err)
-	}
+	cmd.Flags().StringVar(&params.publicKey, "public-key", params.publicKey,
+		"path to the public key to verify the signature of the --input tracking image with")
+
+	cmd.Flags().StringVar(&params.identity.Subject, "certificate-identity", params.identity.Subject,
+		"EXPERIMENTAL. URL of the certificate identity for keyless verification of the --input tracking image")
+
+	cmd.Flags().StringVar(&params.identity.SubjectRegExp, "certificate-identity-regexp", params.identity.SubjectRegExp,
+		"EXPERIMENTAL. Regular expression for the URL of the certificate identity for keyless verification of the --input tracking image")
+
+	cmd.Flags().StringVar(&params.identity.Issuer, "certificate-oidc-issuer", params.identity.Issuer,
+		"EXPERIMENTAL. URL of the certificate OIDC issuer for keyless verification of the --input tracking image")
+
+	cmd.Flags().StringVar(&params.identity.IssuerRegExp, "certificate-oidc-issuer-regexp", params.identity.IssuerRegExp,
+		"EXPERIMENTAL. Regular expresssion for the URL of the certificate OIDC issuer for keyless verification of the --input tracking image")
+
+	cmd.Flags().BoolVar(&params.skipVerify, "insecure-skip-verify", params.skipVerify, hd.Doc(`
+		use the --input tracking image without verifying its signature, as was done before
+		the verification was introduced. Required for --input oci:... unless --public-key or
+		--certificate-identity(-regexp) is set. Not recommended, the tracking data decides
+		which images are acceptable`))
+
+	cmd.Flags().StringVar(&params.rekorURL, "rekor-url", params.rekorURL, hd.Doc(`
+		Rekor URL, the signature of the --input tracking image is verified to be in the
+		transparency log, and the signature of the pushed tracking image is recorded in it`))
+
+	cmd.Flags().StringVar(&params.signing.KeyRef, "signing-key", params.signing.KeyRef, hd.Doc(`
+		path to the private key, or a reference supported by cosign, e.g. k8s://namespace/name,
+		to sign the tracking image pushed to the image registry with. The password of the key,
+		if any, is read from the COSIGN_PASSWORD environment variable`))
+
+	cmd.Flags().BoolVar(&params.signing.Keyless, "keyless", params.signing.Keyless, hd.Doc(`
+		EXPERIMENTAL. sign the tracking image pushed to the image registry with an ephemeral key
+		certified by Fulcio for the OIDC identity, requires --rekor-url`))
+
+	cmd.Flags().StringVar(&params.signing.FulcioURL, "fulcio-url", params.signing.FulcioURL,
+		"EXPERIMENTAL. Fulcio URL to certify the ephemeral key of --keyless with")
+
+	cmd.Flags().StringVar(&params.signing.IDToken, "identity-token", params.signing.IDToken, hd.Doc(`
+		EXPERIMENTAL. OIDC identity token to certify the ephemeral key of --keyless with, if
+		not set the token is obtained through the interactive OIDC flow`))
+
+	cmd.MarkFlagsMutuallyExclusive("signing-key", "keyless")
+	cmd.MarkFlagsMutuallyExclusive("insecure-skip-verify", "public-key")
+	cmd.MarkFlagsMutuallyExclusive("insecure-skip-verify", "certificate-identity")
+	cmd.MarkFlagsMutuallyExclusive("insecure-skip-verify", "certificate-identity-regexp")
 
 	return cmd
 }
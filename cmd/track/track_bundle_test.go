@@ -21,12 +21,20 @@ package track
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/enterprise-contract/ec-cli/internal/tracker"
 	"github.com/enterprise-contract/ec-cli/internal/utils"
 )
 
@@ -40,6 +48,12 @@ func Test_TrackBundleCommand(t *testing.T) {
 		expectInput       string
 		expectStdout      bool
 		expectImageOutput bool
+		expectEffectiveOn map[string]string
+		expectCollection  string
+		expectRepos       []string
+		expectTagRegex    string
+		discovered        []string
+		expectSigning     tracker.SignOptions
 	}{
 		{
 			name: "simple",
@@ -139,6 +153,7 @@ func Test_TrackBundleCommand(t *testing.T) {
 				"oci:registry.io/repository/image:tag",
 				"--output",
 				"oci:registry.io/repository/image:new_tag",
+				"--insecure-skip-verify",
 			},
 			expectInput:       "registry.io/repository/image:tag",
 			expectOutput:      "registry.io/repository/image:new_tag",
@@ -154,6 +169,7 @@ func Test_TrackBundleCommand(t *testing.T) {
 				"registry/image:tag",
 				"--input",
 				"oci:registry.io/repository/image:tag",
+				"--insecure-skip-verify",
 			},
 			expectInput:       "registry.io/repository/image:tag",
 			expectPrune:       true,
@@ -178,6 +194,92 @@ func Test_TrackBundleCommand(t *testing.T) {
 			expectStdout:      false,
 			expectImageOutput: true,
 		},
+		{
+			name: "using OCI with signing",
+			args: []string{
+				"--bundle",
+				"registry/image:tag",
+				"--output",
+				"oci:registry.io/repository/image:tag",
+				"--signing-key",
+				"k8s://namespace/name",
+				"--rekor-url",
+				"https://rekor.example.org",
+			},
+			expectPrune:       true,
+			expectUrls:        []string{"registry/image:tag"},
+			expectOutput:      "registry.io/repository/image:tag",
+			expectStdout:      false,
+			expectImageOutput: true,
+			expectSigning: tracker.SignOptions{
+				KeyRef:    "k8s://namespace/name",
+				FulcioURL: options.DefaultFulcioURL,
+				RekorURL:  "https://rekor.example.org",
+			},
+		},
+		{
+			name: "with effective on",
+			args: []string{
+				"--bundle",
+				"registry/image:tag",
+				"--bundle",
+				"registry/other:tag=2023-06-01",
+				"--effective-on",
+				"2023-07-01T00:00:00Z",
+			},
+			expectUrls:   []string{"registry/image:tag", "registry/other:tag"},
+			expectPrune:  true,
+			expectStdout: true,
+			expectEffectiveOn: map[string]string{
+				"registry/image:tag": "2023-07-01T00:00:00Z",
+				"registry/other:tag": "2023-06-01T00:00:00Z",
+			},
+		},
+		{
+			name: "with collection",
+			args: []string{
+				"--bundle",
+				"registry/image:tag",
+				"--collection",
+				"base-images",
+			},
+			expectUrls:       []string{"registry/image:tag"},
+			expectPrune:      true,
+			expectStdout:     true,
+			expectCollection: "base-images",
+		},
+		{
+			name: "with repository",
+			args: []string{
+				"--bundle",
+				"registry/image:tag",
+				"--repository",
+				"registry/other",
+				"--tag-regex",
+				`v\d+`,
+			},
+			expectUrls:     []string{"registry/image:tag", "registry/other:v1@sha256:abc"},
+			expectPrune:    true,
+			expectStdout:   true,
+			expectRepos:    []string{"registry/other"},
+			expectTagRegex: `v\d+`,
+			discovered:     []string{"registry/other:v1@sha256:abc"},
+		},
+		{
+			name: "with repository only",
+			args: []string{
+				"--repository",
+				"registry/other",
+				"--input",
+				"input-6.json",
+			},
+			expectUrls:   []string{"registry/other:v1@sha256:abc"},
+			expectPrune:  true,
+			expectInput:  "input-6.json",
+			expectStdout: true,
+			expectRepos:  []string{"registry/other"},
+			discovered:   []string{"registry/other:v1@sha256:abc"},
+		},
 	}
 
 	for _, c := range cases {
@@ -191,25 +293,43 @@ func Test_TrackBundleCommand(t *testing.T) {
 				assert.NoError(t, err)
 			}
 			testOutput := `{"test": true}`
-			track := func(_ context.Context, urls []string, input []byte, prune bool) ([]byte, error) {
+			track := func(_ context.Context, urls []string, input []byte, prune bool, effectiveOn tracker.EffectiveOn, collection string) ([]byte, error) {
 				assert.Equal(t, c.expectUrls, urls)
+				assert.Equal(t, c.expectCollection, collection)
+				for url, expected := range c.expectEffectiveOn {
+					assert.Equal(t, expected, effectiveOn.For(url).Format(time.RFC3339))
+				}
 				if c.expectInput != "" {
 					assert.Equal(t, inputData, input)
 				}
 				assert.Equal(t, c.expectPrune, prune)
 				return []byte(testOutput), nil
 			}
-			pullImage := func(_ context.Context, imageRef string) ([]byte, error) {
+			pullImage := func(_ context.Context, imageRef string, checkOpts *cosign.CheckOpts) ([]byte, error) {
 				assert.Equal(t, c.expectInput, imageRef)
+				assert.Nil(t, checkOpts)
 				return inputData, nil
 			}
-			pushImage := func(_ context.Context, imageRef string, data []byte, invocation string) error {
+			pushImage := func(_ context.Context, imageRef string, data []byte, invocation string, signing tracker.SignOptions) error {
 				assert.Equal(t, c.expectOutput, imageRef)
+				if c.expectSigning.Enabled() {
+					assert.Equal(t, c.expectSigning, signing)
+				} else {
+					assert.False(t, signing.Enabled())
+				}
 				assert.Equal(t, testOutput, string(data))
 				assert.NotEmpty(t, invocation) // in tests this will be the cmd.test in temp directory, counting on os.Args to be correct when ec-cli is invoked
 				return nil
 			}
-			cmd := trackBundleCmd(track, pullImage, pushImage)
+			discover := func(_ context.Context, repos []string, tagRegex string, input []byte) ([]string, error) {
+				assert.Equal(t, c.expectRepos, repos)
+				assert.Equal(t, c.expectTagRegex, tagRegex)
+				if c.expectInput != "" {
+					assert.Equal(t, inputData, input)
+				}
+				return c.discovered, nil
+			}
+			cmd := trackBundleCmd(track, pullImage, pushImage, discover)
 			cmd.SetContext(ctx)
 			cmd.SetArgs(c.args)
 			var out bytes.Buffer
@@ -232,3 +352,125 @@ func Test_TrackBundleCommand(t *testing.T) {
 	}
 
 }
+
+func Test_TrackBundleCommandInvalidEffectiveOn(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "default",
+			args: []string{"--bundle", "registry/image:tag", "--effective-on", "soon"},
+			err:  `invalid effective on "soon"`,
+		},
+		{
+			name: "bundle",
+			args: []string{"--bundle", "registry/image:tag=soon"},
+			err:  `bundle registry/image:tag: invalid effective on "soon"`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			track := func(context.Context, []string, []byte, bool, tracker.EffectiveOn, string) ([]byte, error) {
+				t.Fatal("unexpected tracking with an invalid effective on")
+				return nil, nil
+			}
+			cmd := trackBundleCmd(track, nil, nil, nil)
+			cmd.SetContext(utils.WithFS(context.TODO(), afero.NewMemMapFs()))
+			cmd.SetArgs(c.args)
+			cmd.SetOut(&bytes.Buffer{})
+
+			err := cmd.Execute()
+			assert.ErrorContains(t, err, c.err)
+		})
+	}
+}
+
+func Test_TrackBundleCommandRequiresBundleOrRepository(t *testing.T) {
+	cmd := trackBundleCmd(nil, nil, nil, nil)
+	cmd.SetContext(utils.WithFS(context.TODO(), afero.NewMemMapFs()))
+	cmd.SetArgs([]string{})
+	cmd.SetOut(&bytes.Buffer{})
+
+	err := cmd.Execute()
+	assert.EqualError(t, err, "at least one --bundle or --repository is required")
+}
+
+func Test_TrackBundleCommandVerifiesInput(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	publicKey, err := cryptoutils.MarshalPublicKeyToPEM(key.Public())
+	assert.NoError(t, err)
+
+	track := func(context.Context, []string, []byte, bool, tracker.EffectiveOn, string) ([]byte, error) {
+		return []byte(`{"test": true}`), nil
+	}
+	pullImage := func(_ context.Context, imageRef string, checkOpts *cosign.CheckOpts) ([]byte, error) {
+		assert.Equal(t, "registry.io/repository/image:tag", imageRef)
+		if assert.NotNil(t, checkOpts) {
+			assert.NotNil(t, checkOpts.SigVerifier)
+			assert.True(t, checkOpts.IgnoreTlog)
+		}
+		return []byte(`{}`), nil
+	}
+
+	cmd := trackBundleCmd(track, pullImage, nil, nil)
+	cmd.SetContext(utils.WithFS(context.TODO(), afero.NewMemMapFs()))
+	cmd.SetArgs([]string{
+		"--bundle", "registry/image:tag",
+		"--input", "oci:registry.io/repository/image:tag",
+		"--public-key", string(publicKey),
+	})
+	cmd.SetOut(&bytes.Buffer{})
+
+	err = cmd.Execute()
+	assert.NoError(t, err)
+}
+
+func Test_TrackBundleCommandInvalidSigning(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "signing without pushing",
+			args: []string{"--bundle", "registry/image:tag", "--output", "tracked.yaml", "--signing-key", "cosign.key"},
+			err:  "signing requires the tracking data to be written to an image registry",
+		},
+		{
+			name: "keyless without rekor",
+			args: []string{"--bundle", "registry/image:tag", "--output", "oci:registry.io/repository/image:tag", "--keyless"},
+			err:  "--keyless requires --rekor-url",
+		},
+		{
+			name: "key and keyless",
+			args: []string{"--bundle", "registry/image:tag", "--output", "oci:registry.io/repository/image:tag", "--keyless", "--signing-key", "cosign.key"},
+			err:  "if any flags in the group [signing-key keyless] are set none of the others can be",
+		},
+		{
+			name: "unverified input",
+			args: []string{"--bundle", "registry/image:tag", "--input", "oci:registry.io/repository/image:tag"},
+			err:  "verifying the signature of the --input tracking image requires --public-key or --certificate-identity(-regexp)",
+		},
+		{
+			name: "skipped and verified input",
+			args: []string{"--bundle", "registry/image:tag", "--input", "oci:registry.io/repository/image:tag", "--insecure-skip-verify", "--public-key", "cosign.pub"},
+			err:  "if any flags in the group [insecure-skip-verify public-key] are set none of the others can be",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cmd := trackBundleCmd(nil, nil, nil, nil)
+			cmd.SetContext(utils.WithFS(context.TODO(), afero.NewMemMapFs()))
+			cmd.SetArgs(c.args)
+			cmd.SetOut(&bytes.Buffer{})
+
+			err := cmd.Execute()
+			assert.ErrorContains(t, err, c.err)
+		})
+	}
+}
@@ -18,35 +18,44 @@ package validate
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	hd "github.com/MakeNowJust/heredoc"
+	ecc "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
 	"github.com/hashicorp/go-multierror"
 	"github.com/spf13/cobra"
 
 	"github.com/enterprise-contract/ec-cli/internal/definition"
 	"github.com/enterprise-contract/ec-cli/internal/format"
 	"github.com/enterprise-contract/ec-cli/internal/output"
-	"github.com/enterprise-contract/ec-cli/internal/policy/source"
+	"github.com/enterprise-contract/ec-cli/internal/policy"
 	"github.com/enterprise-contract/ec-cli/internal/utils"
 )
 
-type definitionValidationFn func(context.Context, string, []source.PolicySource, []string) (*output.Output, error)
+type definitionValidationFn func(context.Context, string, policy.Policy, []string, bool) (*output.Output, error)
 
 func validateDefinitionCmd(validate definitionValidationFn) *cobra.Command {
 	var data = struct {
-		filePaths  []string
-		policyURLs []string
-		dataURLs   []string
-		output     []string
-		namespaces []string
-		strict     bool
+		filePaths     []string
+		policyURLs    []string
+		dataURLs      []string
+		policyConfig  string
+		effectiveTime string
+		policy        policy.Policy
+		output        []string
+		namespaces    []string
+		exclude       []string
+		info          bool
+		strict        bool
 	}{
 		filePaths:  []string{},
 		policyURLs: []string{"oci::quay.io/hacbs-contract/ec-pipeline-policy:latest"},
 		dataURLs:   []string{"git::https://github.com/enterprise-contract/ec-policies.git//data"},
 		output:     []string{"json"},
 		namespaces: []string{},
+		info:       true,
+		exclude:    []string{},
 	}
 	cmd := &cobra.Command{
 		Use:   "definition",
@@ -68,6 +77,15 @@ func validateDefinitionCmd(validate definitionValidationFn) *cobra.Command {
 
 			  ec validate definition --file </path/to/file> --file /path/to/other.file
 
+			Validate all definition files within a directory, and its subdirectories,
+			except for the ones in the test directories:
+
+			  ec validate definition --file </path/to/directory> --exclude '**/test/**'
+
+			Validate the definition files matching a glob pattern:
+
+			  ec validate definition --file '</path/to/directory>/**/*.yaml'
+
 			Specify --file as JSON
 
 			  ec validate definition --file '{"Kind": "Task"}'
@@ -78,22 +96,59 @@ func validateDefinitionCmd(validate definitionValidationFn) *cobra.Command {
 				--policy git::https://github.com/enterprise-contract/ec-policies//policy/lib \
 				--policy git::https://github.com/enterprise-contract/ec-policies//policy/pipeline \
 				--data git::https://github.com/enterprise-contract/ec-policies//data
+
+			Include the successful checks in a condensed summary of each definition file:
+
+			  ec validate definition --file </path/to/pipeline/file> --show-successes --output summary
+
+			Keep only the code of each rule, without the title, description and solution:
+
+			  ec validate definition --file </path/to/pipeline/file> --info=false
+
+			Use the sources, the rule configuration and the rule data of an
+			EnterpriseContractPolicy:
+
+			  ec validate definition --file </path/to/pipeline/file> --policy-config policy.yaml
 		`),
 
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			// Without an EnterpriseContractPolicy the policy and data
+			// sources form its single source group
+			policyConfig := data.policyConfig
+			if policyConfig == "" {
+				spec, err := json.Marshal(ecc.EnterpriseContractPolicySpec{
+					Sources: []ecc.Source{{Policy: data.policyURLs, Data: data.dataURLs}},
+				})
+				if err != nil {
+					return err
+				}
+				policyConfig = string(spec)
+			} else {
+				var err error
+				if policyConfig, err = resolvePolicyConfiguration(ctx, policyConfig); err != nil {
+					return err
+				}
+			}
+
+			p, err := policy.NewInputPolicy(ctx, policyConfig, data.effectiveTime)
+			if err != nil {
+				return err
+			}
+			data.policy = p
+
+			return nil
+		},
+
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var allErrors error
-			report := definition.NewReport()
+			showSuccesses, _ := cmd.Flags().GetBool("show-successes")
+			report := definition.NewReport(showSuccesses)
 			for i := range data.filePaths {
 				fpath := data.filePaths[i]
-				var sources []source.PolicySource
-				for _, url := range data.policyURLs {
-					sources = append(sources, &source.PolicyUrl{Url: url, Kind: source.PolicyKind})
-				}
-				for _, url := range data.dataURLs {
-					sources = append(sources, &source.PolicyUrl{Url: url, Kind: source.DataKind})
-				}
-				ctx := cmd.Context()
-				if o, err := validate(ctx, fpath, sources, data.namespaces); err != nil {
+				ctx := definition.WithExclude(cmd.Context(), data.exclude)
+				if o, err := validate(ctx, fpath, data.policy, data.namespaces, data.info); err != nil {
 					allErrors = multierror.Append(allErrors, err)
 				} else {
 					report.Add(*o)
@@ -115,8 +170,18 @@ func validateDefinitionCmd(validate definitionValidationFn) *cobra.Command {
 		},
 	}
 
-	cmd.Flags().StringArrayVarP(&data.filePaths, "file", "f", data.filePaths,
-		"path to definition YAML/JSON file (required)")
+	cmd.Flags().StringArrayVarP(&data.filePaths, "file", "f", data.filePaths, hd.Doc(`
+		path to definition YAML/JSON file (required). Directories are walked
+		recursively for JSON and YAML files, glob patterns, including "**" for
+		any number of directories, are supported. Each document of a
+		multi-document YAML file is reported separately as <file>#<n>.
+	`))
+
+	cmd.Flags().StringSliceVar(&data.exclude, "exclude", data.exclude, hd.Doc(`
+		glob pattern of the definition files to leave out when walking a
+		directory or matching a glob pattern, e.g. '**/test/**'. Patterns
+		without a "/" are matched against the file name. May be used multiple times
+	`))
 
 	cmd.Flags().StringSliceVar(&data.policyURLs, "policy", data.policyURLs,
 		"url for policies, go-getter style. May be used multiple times")
@@ -124,12 +189,33 @@ func validateDefinitionCmd(validate definitionValidationFn) *cobra.Command {
 	cmd.Flags().StringSliceVar(&data.dataURLs, "data", data.dataURLs,
 		"url for policy data, go-getter style. May be used multiple times")
 
+	cmd.Flags().StringVar(&data.policyConfig, "policy-config", data.policyConfig, hd.Doc(`
+		EnterpriseContractPolicy providing the policy and data sources, and the
+		configuration and rule data used in their evaluation, as:
+		  * Kubernetes reference ([<namespace>/]<name>)
+		  * file (policy.yaml)
+		  * git reference (github.com/user/repo//default?ref=main), or
+		  * inline JSON ('{sources: {...}, configuration: {...}}')
+		Can't be combined with --policy or --data.`))
+
+	cmd.Flags().StringVar(&data.effectiveTime, "effective-time", policy.Now, hd.Doc(`
+		Run policy checks with the provided time. Useful for testing rules with
+		effective dates in the future. The value can be "now" (default) - for
+		current time, or a RFC3339 formatted value, e.g. 2022-11-18T00:00:00Z.
+	`))
+
 	cmd.Flags().StringSliceVarP(&data.output, "output", "o", data.output, hd.Doc(`
 		write output to a file in a specific format, e.g. yaml=/tmp/output.yaml. Use empty string
-		path for stdout, e.g. yaml. May be used multiple times. Possible formats are json and yaml
+		path for stdout, e.g. yaml. May be used multiple times. Possible formats are json, yaml,
+		sarif, summary, junit and text. The text format is colored when written to a terminal.
 	`))
 	cmd.Flags().StringSliceVar(&data.namespaces, "namespace", data.namespaces,
 		"the namespace containing the policy to run. May be used multiple times")
+	cmd.Flags().BoolVar(&data.info, "info", data.info, hd.Doc(`
+		Include additional information on the failures. For instance for policy
+		violations, include the title and the description of the failed policy
+		rule. Enabled by default, use --info=false to keep only the code of each
+		rule.`))
 	cmd.Flags().BoolVarP(&data.strict, "strict", "s", data.strict,
 		"return non-zero status on non-successful validation")
 
@@ -137,5 +223,8 @@ func validateDefinitionCmd(validate definitionValidationFn) *cobra.Command {
 		panic(err)
 	}
 
+	cmd.MarkFlagsMutuallyExclusive("policy-config", "policy")
+	cmd.MarkFlagsMutuallyExclusive("policy-config", "data")
+
 	return cmd
 }
@@ -23,20 +23,24 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	hd "github.com/MakeNowJust/heredoc"
+	ecc "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
 	"github.com/open-policy-agent/conftest/output"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/enterprise-contract/ec-cli/internal/evaluator"
 	output2 "github.com/enterprise-contract/ec-cli/internal/output"
-	"github.com/enterprise-contract/ec-cli/internal/policy/source"
+	"github.com/enterprise-contract/ec-cli/internal/policy"
 	"github.com/enterprise-contract/ec-cli/internal/utils"
 )
 
 func TestValidateDefinitionFileCommandOutput(t *testing.T) {
-	validate := func(_ context.Context, fpath string, _ []source.PolicySource, _ []string) (*output2.Output, error) {
+	validate := func(_ context.Context, fpath string, _ policy.Policy, _ []string, detailed bool) (*output2.Output, error) {
+		// the rule metadata is kept by default
+		assert.True(t, detailed)
 		return &output2.Output{PolicyCheck: evaluator.CheckResults{{CheckResult: output.CheckResult{FileName: fpath}}}}, nil
 	}
 
@@ -74,15 +78,78 @@ func TestValidateDefinitionFileCommandOutput(t *testing.T) {
 	  }`, out.String())
 }
 
+func TestValidateDefinitionFileCommandSuccessesAndInfo(t *testing.T) {
+	validate := func(_ context.Context, fpath string, _ policy.Policy, _ []string, detailed bool) (*output2.Output, error) {
+		assert.True(t, detailed)
+		return &output2.Output{PolicyCheck: evaluator.CheckResults{{CheckResult: output.CheckResult{
+			FileName: fpath,
+			Successes: []output.Result{
+				{Message: "Pass", Metadata: map[string]interface{}{"code": "pipeline.spam", "title": "Spam"}},
+			},
+		}}}}, nil
+	}
+
+	cmd := validateDefinitionCmd(validate)
+	cmd.Flags().Bool("show-successes", false, "")
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	cmd.SetArgs([]string{
+		"--file",
+		"/path/file1.yaml",
+		"--info",
+		"--show-successes",
+		"--output",
+		"summary",
+	})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, `{"definitions": [
+		{
+		  "filename": "/path/file1.yaml",
+		  "success": true,
+		  "violations": {},
+		  "warnings": {},
+		  "successes": {"pipeline.spam": ["Pass"]},
+		  "total_violations": 0,
+		  "total_warnings": 0,
+		  "total_successes": 1
+		}
+	  ],
+	  "success": true
+	  }`, out.String())
+}
+
+func TestValidateDefinitionFileCommandWithoutInfo(t *testing.T) {
+	validate := func(_ context.Context, fpath string, _ policy.Policy, _ []string, detailed bool) (*output2.Output, error) {
+		assert.False(t, detailed)
+		return &output2.Output{}, nil
+	}
+
+	cmd := validateDefinitionCmd(validate)
+	cmd.SetOut(&bytes.Buffer{})
+
+	cmd.SetArgs([]string{
+		"--file",
+		"/path/file1.yaml",
+		"--info=false",
+	})
+
+	assert.NoError(t, cmd.Execute())
+}
+
 func TestValidateDefinitionFilePolicySources(t *testing.T) {
-	expected := []source.PolicySource{
-		&source.PolicyUrl{Url: "spam-policy-source", Kind: source.PolicyKind},
-		&source.PolicyUrl{Url: "ham-policy-source", Kind: source.PolicyKind},
-		&source.PolicyUrl{Url: "bacon-data-source", Kind: source.DataKind},
-		&source.PolicyUrl{Url: "eggs-data-source", Kind: source.DataKind},
+	expected := []ecc.Source{
+		{
+			Policy: []string{"spam-policy-source", "ham-policy-source"},
+			Data:   []string{"bacon-data-source", "eggs-data-source"},
+		},
 	}
-	validate := func(_ context.Context, fpath string, sources []source.PolicySource, _ []string) (*output2.Output, error) {
-		assert.Equal(t, expected, sources)
+	validate := func(_ context.Context, fpath string, p policy.Policy, _ []string, _ bool) (*output2.Output, error) {
+		assert.Equal(t, expected, p.Spec().Sources)
 		return &output2.Output{}, nil
 	}
 
@@ -108,6 +175,54 @@ func TestValidateDefinitionFilePolicySources(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestValidateDefinitionFilePolicyConfig(t *testing.T) {
+	validate := func(_ context.Context, fpath string, p policy.Policy, _ []string, _ bool) (*output2.Output, error) {
+		assert.Equal(t, []ecc.Source{{Name: "pipelines", Policy: []string{"spam-policy-source"}}}, p.Spec().Sources)
+		assert.Equal(t, []string{"pipeline.basic"}, p.Spec().Configuration.Include)
+		assert.Equal(t, "2022-11-23T16:30:00Z", p.EffectiveTime().Format(time.RFC3339))
+		return &output2.Output{}, nil
+	}
+
+	cmd := validateDefinitionCmd(validate)
+	cmd.SetContext(utils.WithFS(context.Background(), afero.NewMemMapFs()))
+	cmd.SetOut(&bytes.Buffer{})
+
+	cmd.SetArgs([]string{
+		"--file",
+		"/path/file1.yaml",
+		"--policy-config",
+		`{"sources": [{"name": "pipelines", "policy": ["spam-policy-source"]}], "configuration": {"include": ["pipeline.basic"]}}`,
+		"--effective-time",
+		"2022-11-23T16:30:00Z",
+	})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+}
+
+func TestValidateDefinitionFilePolicyConfigExclusive(t *testing.T) {
+	validate := func(context.Context, string, policy.Policy, []string, bool) (*output2.Output, error) {
+		return &output2.Output{}, nil
+	}
+
+	cmd := validateDefinitionCmd(validate)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+
+	cmd.SetArgs([]string{
+		"--file",
+		"/path/file1.yaml",
+		"--policy-config",
+		`{"sources": []}`,
+		"--policy",
+		"spam-policy-source",
+	})
+
+	err := cmd.Execute()
+	assert.ErrorContains(t, err, "if any flags in the group [policy-config policy] are set none of the others can be")
+}
+
 func TestDefinitionFileOutputFormats(t *testing.T) {
 	testJSONText := `{"definitions":[{"filename":"/path/file1.yaml","violations":[],"warnings":[],"success":true}],"success":true,"ec-version":"development"}`
 
@@ -155,7 +270,7 @@ func TestDefinitionFileOutputFormats(t *testing.T) {
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
 			fs := afero.NewMemMapFs()
-			validate := func(_ context.Context, fpath string, sources []source.PolicySource, _ []string) (*output2.Output, error) {
+			validate := func(_ context.Context, fpath string, _ policy.Policy, _ []string, _ bool) (*output2.Output, error) {
 				return &output2.Output{PolicyCheck: evaluator.CheckResults{{CheckResult: output.CheckResult{FileName: fpath}}}}, nil
 			}
 
@@ -185,7 +300,7 @@ func TestDefinitionFileOutputFormats(t *testing.T) {
 }
 
 func TestValidateDefinitionFileCommandErrors(t *testing.T) {
-	validate := func(_ context.Context, fpath string, _ []source.PolicySource, _ []string) (*output2.Output, error) {
+	validate := func(_ context.Context, fpath string, _ policy.Policy, _ []string, _ bool) (*output2.Output, error) {
 		return nil, errors.New(fpath)
 	}
 
@@ -208,7 +323,7 @@ func TestValidateDefinitionFileCommandErrors(t *testing.T) {
 }
 
 func TestStrictOutput(t *testing.T) {
-	validate := func(_ context.Context, fpath string, _ []source.PolicySource, _ []string) (*output2.Output, error) {
+	validate := func(_ context.Context, fpath string, _ policy.Policy, _ []string, _ bool) (*output2.Output, error) {
 		failureResult := output.CheckResult{
 			FileName: fpath,
 			Failures: []output.Result{
@@ -20,33 +20,41 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"sync"
+	"time"
 
 	hd "github.com/MakeNowJust/heredoc"
 	"github.com/hashicorp/go-multierror"
+	"github.com/qri-io/jsonschema"
 	app "github.com/redhat-appstudio/application-api/api/v1alpha1"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
-	log "github.com/sirupsen/logrus"
-	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 
 	"github.com/enterprise-contract/ec-cli/internal/applicationsnapshot"
+	"github.com/enterprise-contract/ec-cli/internal/evaluation_target/application_snapshot_image"
 	"github.com/enterprise-contract/ec-cli/internal/evaluator"
 	"github.com/enterprise-contract/ec-cli/internal/format"
 	"github.com/enterprise-contract/ec-cli/internal/output"
 	"github.com/enterprise-contract/ec-cli/internal/policy"
 	"github.com/enterprise-contract/ec-cli/internal/policy/source"
 	"github.com/enterprise-contract/ec-cli/internal/utils"
+	"github.com/enterprise-contract/ec-cli/internal/vsa"
 )
 
 type imageValidationFunc func(context.Context, string, policy.Policy, bool) (*output.Output, error)
 
 func validateImageCmd(validate imageValidationFunc) *cobra.Command {
 	var data = struct {
+		attestationSchemas          []string
+		schemas                     map[string]*jsonschema.Schema
 		certificateIdentity         string
 		certificateIdentityRegExp   string
 		certificateOIDCIssuer       string
 		certificateOIDCIssuerRegExp string
+		componentTimeout            time.Duration
+		concurrency                 int
 		effectiveTime               string
 		filePath                    string
 		imageRef                    string
@@ -55,16 +63,21 @@ func validateImageCmd(validate imageValidationFunc) *cobra.Command {
 		output                      []string
 		outputFile                  string
 		policy                      policy.Policy
+		policyCacheDir              string
 		policyConfiguration         string
 		publicKey                   string
 		rekorURL                    string
 		snapshot                    string
 		spec                        *app.SnapshotSpec
 		strict                      bool
+		vsa                         vsaOptions
+		workers                     int
 	}{
 
 		// Default policy from an ECP cluster resource
 		policyConfiguration: "enterprise-contract-service/default",
+		workers:             utils.DefaultWorkers,
+		concurrency:         5,
 	}
 	cmd := &cobra.Command{
 		Use:   "image",
@@ -152,6 +165,28 @@ func validateImageCmd(validate imageValidationFunc) *cobra.Command {
 
 			  ec validate image --image registry/name:tag --output yaml --output appstudio=<path>
 
+			Validate an image, its signatures and attestations exported to a local OCI layout
+			directory or tarball, e.g. using "cosign save", without accessing the registry.
+			Policy sources are still fetched, and keyless verification or verification
+			against Rekor still require network access. To validate fully offline, use local
+			policy sources and a public key, and don't configure a Rekor URL:
+
+			  ec validate image --image oci:path/to/layout --public-key <path/to/public/key> \
+			    --policy '{"sources":[{"policy":["path/to/policy"]}]}'
+
+			Validate the syntax of SPDX SBOM attestations using a custom JSON schema:
+
+			  ec validate image --image registry/name:tag \
+			    --attestation-schema https://spdx.dev/Document=spdx.schema.json
+
+			Push a signed SLSA Verification Summary Attestation (VSA) of the image to the registry:
+
+			  ec validate image --image registry/name:tag --vsa-signing-key cosign.key --vsa-upload
+
+			Skip the evaluation of images with a VSA of the same policy, less than an hour old:
+
+			  ec validate image --image registry/name:tag --vsa-trusted-key cosign.pub --vsa-max-age 1h
+
 			Write the data used in the policy evaluation to a file in YAML format
 
 			  ec validate image --image registry/name:tag --output data=<path>
@@ -194,49 +229,56 @@ func validateImageCmd(validate imageValidationFunc) *cobra.Command {
 				SubjectRegExp: data.certificateIdentityRegExp,
 			}
 
-			// Check if policyConfiguration is a git url, if so, try to download a config file from git
-			if source.SourceIsGit(data.policyConfiguration) {
-				log.Debugf("Fetching policy config from git url %s", data.policyConfiguration)
+			// The policy is identified by its reference in the VSAs, unless
+			// provided inline
+			if source.SourceIsGit(data.policyConfiguration) || utils.HasJsonOrYamlExt(data.policyConfiguration) || !strings.Contains(data.policyConfiguration, ":") {
+				data.vsa.policyURI = data.policyConfiguration
+			}
 
-				// Create a temporary dir to download the config. It will be different to the
-				// workdir used later for downloading policy sources, but it won't matter
-				// because this dir is not used again once the config file has been read.
-				fs := utils.FS(ctx)
-				tmpDir, err := utils.CreateWorkDir(fs)
-				if err != nil {
-					allErrors = multierror.Append(allErrors, err)
-					return
-				}
-				defer utils.CleanupWorkDir(fs, tmpDir)
+			if policyConfiguration, err := resolvePolicyConfiguration(ctx, data.policyConfiguration); err != nil {
+				allErrors = multierror.Append(allErrors, err)
+				return
+			} else {
+				data.policyConfiguration = policyConfiguration
+			}
 
-				// Git download and find a suitable config file
-				configFile, err := source.GitConfigDownload(cmd.Context(), tmpDir, data.policyConfiguration)
-				if err != nil {
-					allErrors = multierror.Append(allErrors, err)
-					return
+			if data.concurrency < 1 {
+				allErrors = multierror.Append(allErrors, fmt.Errorf("invalid concurrency %d, must be at least 1", data.concurrency))
+			}
+
+			if data.vsa.signingKey != "" && data.vsa.outputDir == "" && !data.vsa.upload {
+				allErrors = multierror.Append(allErrors, errors.New("--vsa-signing-key requires --vsa-output-dir and/or --vsa-upload"))
+			}
+
+			if data.vsa.trustedKey != "" {
+				if checker, err := vsa.NewChecker(ctx, data.vsa.trustedKey, data.vsa.maxAge); err != nil {
+					allErrors = multierror.Append(allErrors, fmt.Errorf("unable to load the trusted VSA key: %w", err))
+				} else {
+					data.vsa.checker = checker
 				}
+			}
 
-				// Changing data.policyConfiguration to the name of the newly downloaded
-				// file means we can use the code below to load the config
-				data.policyConfiguration = configFile
+			if data.workers < 1 {
+				allErrors = multierror.Append(allErrors, fmt.Errorf("invalid number of workers %d, must be at least 1", data.workers))
 			}
 
-			// Check if policyConfiguration is a file path, if so, we read it into the var data.policyConfiguration
-			if utils.HasJsonOrYamlExt(data.policyConfiguration) {
-				fs := utils.FS(ctx)
-				policyBytes, err := afero.ReadFile(fs, data.policyConfiguration)
-				if err != nil {
-					allErrors = multierror.Append(allErrors, err)
-					return
+			for _, s := range data.attestationSchemas {
+				predicateType, location, ok := strings.Cut(s, "=")
+				if !ok || predicateType == "" || location == "" {
+					allErrors = multierror.Append(allErrors, fmt.Errorf("invalid attestation schema %q, expected <predicateType>=<file|URL>", s))
+					continue
 				}
-				// Check for empty file as that would cause a false "success"
-				if len(policyBytes) == 0 {
-					err := fmt.Errorf("file %s is empty", data.policyConfiguration)
+
+				schema, err := application_snapshot_image.LoadAttestationSchema(ctx, location)
+				if err != nil {
 					allErrors = multierror.Append(allErrors, err)
-					return
+					continue
 				}
 
-				data.policyConfiguration = string(policyBytes)
+				if data.schemas == nil {
+					data.schemas = map[string]*jsonschema.Schema{}
+				}
+				data.schemas[predicateType] = schema
 			}
 
 			if p, err := policy.NewPolicy(
@@ -258,18 +300,58 @@ func validateImageCmd(validate imageValidationFunc) *cobra.Command {
 				data      []evaluator.Data
 			}
 
+			// Policy sources are downloaded once and shared by all components
+			cache, err := source.NewCache(cmd.Context(), data.policyCacheDir)
+			if err != nil {
+				return err
+			}
+			defer cache.Cleanup()
+
+			ctx := utils.WithWorkers(cmd.Context(), data.workers)
+			ctx = source.WithCache(ctx, cache)
+			if len(data.schemas) > 0 {
+				ctx = application_snapshot_image.WithAttestationSchemas(ctx, data.schemas)
+			}
+			if data.vsa.checker != nil {
+				ctx = vsa.WithChecker(ctx, data.vsa.checker)
+			}
+
 			appComponents := data.spec.Components
 
 			ch := make(chan result, len(appComponents))
 
+			// Progress is redrawn in place on a terminal, otherwise, e.g. in
+			// CI, a line is written on each change. Validating a single
+			// component reports no progress worth logging so it is reported
+			// only to a terminal.
+			stderr := cmd.ErrOrStderr()
+			interactive := utils.IsTerminal(stderr)
+			progressOut := stderr
+			if !interactive && len(appComponents) < 2 {
+				progressOut = io.Discard
+			}
+			progress := newProgress(progressOut, len(appComponents), interactive)
+
+			// limits the number of components validated at the same time
+			slots := make(chan struct{}, data.concurrency)
+
 			var lock sync.WaitGroup
 			for _, c := range appComponents {
 				lock.Add(1)
 				go func(comp app.SnapshotComponent) {
 					defer lock.Done()
 
-					ctx := cmd.Context()
-					out, err := validate(ctx, comp.ContainerImage, data.policy, data.info)
+					slots <- struct{}{}
+					defer func() { <-slots }()
+
+					name := comp.Name
+					if name == "" {
+						name = comp.ContainerImage
+					}
+					progress.start(name)
+					defer progress.finish(name)
+
+					out, err := validateComponent(evaluator.WithComponent(ctx, comp.Name), validate, comp.ContainerImage, data.policy, data.info, data.componentTimeout)
 					res := result{
 						err: err,
 						component: applicationsnapshot.Component{
@@ -286,10 +368,13 @@ func validateImageCmd(validate imageValidationFunc) *cobra.Command {
 						res.component.Violations = out.Violations()
 						showSuccesses, _ := cmd.Flags().GetBool("show-successes")
 						res.component.Warnings = out.Warnings()
+						res.component.FutureViolations = out.FutureViolations()
+						res.component.Exempted = out.Exempted()
 						if showSuccesses {
 							res.component.Successes = out.Successes()
 						}
 						res.component.Signatures = out.Signatures
+						res.component.Rules = out.Rules
 						res.component.ContainerImage = out.ImageURL
 						res.data = out.Data
 					}
@@ -331,6 +416,12 @@ func validateImageCmd(validate imageValidationFunc) *cobra.Command {
 				return err
 			}
 
+			if data.vsa.signingKey != "" {
+				if err := createVSAs(cmd.Context(), report, data.vsa); err != nil {
+					return err
+				}
+			}
+
 			if data.strict && !report.Success {
 				// TODO: replace this with proper message and exit code 1.
 				return errors.New("success criteria not met")
@@ -347,7 +438,10 @@ func validateImageCmd(validate imageValidationFunc) *cobra.Command {
 		  * git reference (github.com/user/repo//default?ref=main), or
 		  * inline JSON ('{sources: {...}, configuration: {...}}')")`))
 
-	cmd.Flags().StringVarP(&data.imageRef, "image", "i", data.imageRef, "OCI image reference")
+	cmd.Flags().StringVarP(&data.imageRef, "image", "i", data.imageRef, hd.Doc(`
+		OCI image reference, or oci:<path> to read the image, its signatures and
+		attestations from a local OCI layout directory or tarball. Only access to
+		the registry is avoided, see the examples.`))
 
 	cmd.Flags().StringVarP(&data.publicKey, "public-key", "k", data.publicKey,
 		"path to the public key. Overrides publicKey from EnterpriseContractPolicy")
@@ -376,7 +470,9 @@ func validateImageCmd(validate imageValidationFunc) *cobra.Command {
 	cmd.Flags().StringSliceVar(&data.output, "output", data.output, hd.Doc(`
 		write output to a file in a specific format. Use empty string path for stdout.
 		May be used multiple times. Possible formats are json, yaml, appstudio, junit,
-		summary and data.
+		sarif, markdown, summary, text and data. The text format is colored when written to a
+		terminal. Use template:<path> to render the report with the Go template at path,
+		e.g. template:report.tmpl=report.txt.
 	`))
 
 	cmd.Flags().StringVarP(&data.outputFile, "output-file", "o", data.outputFile,
@@ -396,11 +492,62 @@ func validateImageCmd(validate imageValidationFunc) *cobra.Command {
 		Provide the AppStudio Snapshot as a source of the images to validate, as inline
 		JSON of the "spec" or a reference to a Kubernetes object [<namespace>/]<name>`))
 
+	cmd.Flags().StringSliceVar(&data.attestationSchemas, "attestation-schema", data.attestationSchemas, hd.Doc(`
+		JSON schema used to validate the syntax of attestations of the given
+		predicate type, in the form of <predicateType>=<file|URL>. Takes precedence
+		over the built-in schema for the same predicate type. May be used multiple
+		times.`))
+
 	cmd.Flags().BoolVar(&data.info, "info", data.info, hd.Doc(`
 		Include additional information on the failures. For instance for policy
 		violations, include the title and the description of the failed policy
 		rule.`))
 
+	cmd.Flags().StringVar(&data.policyCacheDir, "policy-cache-dir", data.policyCacheDir, hd.Doc(`
+		Directory used to persist downloaded policy and data sources across runs.
+		Only sources pinned by a digest (OCI) or a full commit SHA (git) are
+		persisted, others are downloaded once per run.`))
+
+	cmd.Flags().IntVar(&data.concurrency, "concurrency", data.concurrency,
+		"Number of components validated concurrently")
+
+	cmd.Flags().DurationVar(&data.componentTimeout, "component-timeout", data.componentTimeout, hd.Doc(`
+		Max duration of the validation of a single component, e.g. 5m. Zero, the
+		default, means no limit other than the overall --timeout.`))
+
+	cmd.Flags().IntVar(&data.workers, "workers", data.workers, hd.Doc(`
+		Number of policy source groups fetched and evaluated concurrently for each
+		image. The results are reported in the order of the source groups.`))
+
+	cmd.Flags().StringVar(&data.vsa.signingKey, "vsa-signing-key", data.vsa.signingKey, hd.Doc(`
+		Reference to the private key used to sign a SLSA Verification Summary
+		Attestation (VSA) of each component, e.g. a file path or k8s://ns/name.
+		The password of the key is read from the COSIGN_PASSWORD environment
+		variable. Requires --vsa-output-dir and/or --vsa-upload.`))
+
+	cmd.Flags().StringVar(&data.vsa.outputDir, "vsa-output-dir", data.vsa.outputDir,
+		"Directory to write the signed VSA of each component to, as <component>.vsa.json")
+
+	cmd.Flags().BoolVar(&data.vsa.upload, "vsa-upload", data.vsa.upload, hd.Doc(`
+		Push the signed VSA of each component to the registry as a cosign
+		attestation of the component's image, replacing any previous VSA.`))
+
+	cmd.Flags().StringSliceVar(&data.vsa.verifiedLevels, "vsa-verified-level", data.vsa.verifiedLevels, hd.Doc(`
+		Level recorded as verified in the VSA of the components that pass the
+		validation, e.g. SLSA_BUILD_LEVEL_3. Can be repeated.`))
+
+	cmd.Flags().StringVar(&data.vsa.trustedKey, "vsa-trusted-key", data.vsa.trustedKey, hd.Doc(`
+		Reference to the public key of a trusted VSA signer, e.g. a file path or
+		k8s://ns/name. An image with a VSA signed by this key, recording that it
+		passed the validation against the same policy, is reported as passing
+		without evaluating the policy again. The same policy includes the same
+		component overrides, the same exceptions still active, and the same
+		--effective-time. Images without such a VSA are fully validated.`))
+
+	cmd.Flags().DurationVar(&data.vsa.maxAge, "vsa-max-age", 24*time.Hour, hd.Doc(`
+		Max age of a trusted VSA, e.g. 12h. Older VSAs are ignored. Zero means
+		no limit.`))
+
 	if len(data.input) > 0 || len(data.filePath) > 0 {
 		if err := cmd.MarkFlagRequired("image"); err != nil {
 			panic(err)
@@ -409,3 +556,23 @@ func validateImageCmd(validate imageValidationFunc) *cobra.Command {
 
 	return cmd
 }
+
+// validateComponent invokes validate for the image, giving up once the
+// timeout, if greater than zero, elapses.
+func validateComponent(ctx context.Context, validate imageValidationFunc, image string, p policy.Policy, info bool, timeout time.Duration) (*output.Output, error) {
+	if timeout <= 0 {
+		return validate(ctx, image, p, info)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// validate is invoked within the slot of the component so that abandoned
+	// validations don't pile up, it stops as soon as the context is done
+	out, err := validate(ctx, image, p, info)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("validation did not complete within %s: %w", timeout, ctx.Err())
+	}
+
+	return out, err
+}
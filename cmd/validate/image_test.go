@@ -582,6 +582,36 @@ func Test_ValidateErrorCommand(t *testing.T) {
 	* unable to parse Snapshot specification from input: error converting YAML to JSON: yaml: found unexpected end of stream
 	* unable to parse EnterpriseContractPolicySpec: error converting YAML to JSON: yaml: found unexpected end of stream
 
+`,
+		},
+		{
+			name: "invalid attestation schema",
+			args: []string{
+				"--image",
+				"registry/image:tag",
+				"--policy",
+				fmt.Sprintf(`{"publicKey": %s}`, utils.TestPublicKeyJSON),
+				"--attestation-schema",
+				"https://spdx.dev/Document",
+			},
+			expected: `1 error occurred:
+	* invalid attestation schema "https://spdx.dev/Document", expected <predicateType>=<file|URL>
+
+`,
+		},
+		{
+			name: "VSA signing key without destination",
+			args: []string{
+				"--image",
+				"registry/image:tag",
+				"--policy",
+				fmt.Sprintf(`{"publicKey": %s}`, utils.TestPublicKeyJSON),
+				"--vsa-signing-key",
+				"cosign.key",
+			},
+			expected: `1 error occurred:
+	* --vsa-signing-key requires --vsa-output-dir and/or --vsa-upload
+
 `,
 		},
 	}
@@ -798,3 +828,27 @@ func Test_WarningOutput(t *testing.T) {
 		}
 	  }`, effectiveTimeTest, utils.TestPublicKeyJSON, utils.TestPublicKeyJSON), out.String())
 }
+
+func Test_ValidateComponentTimeout(t *testing.T) {
+	returned := false
+	validate := func(ctx context.Context, url string, _ policy.Policy, _ bool) (*output.Output, error) {
+		defer func() { returned = true }()
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(10 * time.Second):
+			return &output.Output{ImageURL: url}, nil
+		}
+	}
+
+	_, err := validateComponent(context.Background(), validate, "registry/image:tag", nil, false, 10*time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	// the validation is not left running in the background
+	assert.True(t, returned)
+
+	out, err := validateComponent(context.Background(), func(_ context.Context, url string, _ policy.Policy, _ bool) (*output.Output, error) {
+		return &output.Output{ImageURL: url}, nil
+	}, "registry/image:tag", nil, false, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "registry/image:tag", out.ImageURL)
+}
@@ -0,0 +1,202 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	hd "github.com/MakeNowJust/heredoc"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/cobra"
+
+	"github.com/enterprise-contract/ec-cli/internal/format"
+	"github.com/enterprise-contract/ec-cli/internal/input"
+	"github.com/enterprise-contract/ec-cli/internal/output"
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+type inputValidationFunc func(context.Context, []string, policy.Policy, bool) (*output.Output, error)
+
+func validateInputCmd(validate inputValidationFunc) *cobra.Command {
+	var data = struct {
+		effectiveTime       string
+		filePaths           []string
+		info                bool
+		output              []string
+		policy              policy.Policy
+		policyConfiguration string
+		strict              bool
+	}{
+		filePaths: []string{},
+		output:    []string{input.JSON},
+	}
+	cmd := &cobra.Command{
+		Use:   "input",
+		Short: "Validate arbitrary JSON or YAML file input conformance with the Enterprise Contract",
+
+		Long: hd.Doc(`
+			Validate arbitrary JSON or YAML file input conformance with the Enterprise Contract
+
+			Each input document is evaluated against the policy sources of the
+			EnterpriseContractPolicy, honoring its configuration, i.e. the included
+			and excluded rules, its rule data and the effective time. This allows
+			using the same policy configuration format used for images to validate
+			documents like Tekton PipelineRuns or release plans.
+		`),
+
+		Example: hd.Doc(`
+			Validate a file with the policy from a local EnterpriseContractPolicy spec:
+
+			  ec validate input --file pipeline-run.yaml --policy policy.yaml
+
+			Validate all JSON and YAML files in a directory:
+
+			  ec validate input --file manifests/ --policy policy.yaml
+
+			Validate a document read from standard input:
+
+			  cat release-plan.json | ec validate input --file - --policy policy.yaml
+
+			Use a git url for the policy configuration:
+
+			  ec validate input --file pipeline-run.yaml --policy github.com/user/repo//default?ref=main
+		`),
+
+		PreRunE: func(cmd *cobra.Command, args []string) (allErrors error) {
+			ctx := cmd.Context()
+
+			policyConfiguration, err := resolvePolicyConfiguration(ctx, data.policyConfiguration)
+			if err != nil {
+				allErrors = multierror.Append(allErrors, err)
+				return
+			}
+
+			if p, err := policy.NewInputPolicy(ctx, policyConfiguration, data.effectiveTime); err != nil {
+				allErrors = multierror.Append(allErrors, err)
+			} else {
+				data.policy = p
+			}
+
+			return
+		},
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			showSuccesses, _ := cmd.Flags().GetBool("show-successes")
+			report := input.NewReport(data.policy, showSuccesses)
+
+			// The document read from stdin is stored in a temporary file, and
+			// reported as stdin
+			fs := utils.FS(ctx)
+			fpaths := make([]string, 0, len(data.filePaths))
+			names := map[string]string{}
+			for _, fpath := range data.filePaths {
+				if fpath == "-" {
+					stdin, err := io.ReadAll(cmd.InOrStdin())
+					if err != nil {
+						return err
+					}
+
+					tmp, err := utils.WriteTempFile(ctx, string(stdin), "input-")
+					if err != nil {
+						return err
+					}
+					defer fs.Remove(tmp) //nolint:errcheck
+
+					names[tmp] = "stdin"
+					fpath = tmp
+				}
+				fpaths = append(fpaths, fpath)
+			}
+
+			o, err := validate(ctx, fpaths, data.policy, data.info)
+			if err != nil {
+				return fmt.Errorf("error validating input %s: %w", strings.Join(data.filePaths, ", "), err)
+			}
+
+			for i := range o.PolicyCheck {
+				if name, ok := names[o.PolicyCheck[i].FileName]; ok {
+					o.PolicyCheck[i].FileName = name
+				}
+			}
+			report.Add(*o)
+
+			var allErrors error
+			p := format.NewTargetParser(input.JSON, cmd.OutOrStdout(), utils.FS(ctx))
+			for _, target := range data.output {
+				if err := report.Write(target, p); err != nil {
+					allErrors = multierror.Append(allErrors, err)
+				}
+			}
+			if allErrors != nil {
+				return allErrors
+			}
+
+			if data.strict && !report.Success {
+				return errors.New("success criteria not met")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&data.filePaths, "file", "f", data.filePaths, hd.Doc(`
+		path to a JSON or YAML file, or a directory of such files, to validate. Use -
+		to read from stdin. May be used multiple times (required)`))
+
+	cmd.Flags().StringVarP(&data.policyConfiguration, "policy", "p", data.policyConfiguration, hd.Doc(`
+		Policy configuration (required) as:
+		  * Kubernetes reference ([<namespace>/]<name>)
+		  * file (policy.yaml)
+		  * git reference (github.com/user/repo//default?ref=main), or
+		  * inline JSON ('{sources: {...}, configuration: {...}}')`))
+
+	cmd.Flags().StringSliceVarP(&data.output, "output", "o", data.output, hd.Doc(`
+		write output to a file in a specific format, e.g. yaml=/tmp/output.yaml. Use empty string
+		path for stdout, e.g. yaml. May be used multiple times. Possible formats are json, yaml,
+		sarif and text. The text format is colored when written to a terminal.
+	`))
+
+	cmd.Flags().StringVar(&data.effectiveTime, "effective-time", policy.Now, hd.Doc(`
+		Run policy checks with the provided time. Useful for testing rules with
+		effective dates in the future. The value can be "now" (default) - for
+		current time, or a RFC3339 formatted value, e.g. 2022-11-18T00:00:00Z.
+	`))
+
+	cmd.Flags().BoolVar(&data.info, "info", data.info, hd.Doc(`
+		Include additional information on the failures. For instance for policy
+		violations, include the title and the description of the failed policy
+		rule.`))
+
+	cmd.Flags().BoolVarP(&data.strict, "strict", "s", data.strict,
+		"return non-zero status on non-successful validation")
+
+	if err := cmd.MarkFlagRequired("file"); err != nil {
+		panic(err)
+	}
+
+	if err := cmd.MarkFlagRequired("policy"); err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
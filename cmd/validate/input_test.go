@@ -0,0 +1,154 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package validate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-policy-agent/conftest/output"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/evaluator"
+	output2 "github.com/enterprise-contract/ec-cli/internal/output"
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+func TestValidateInputCommand(t *testing.T) {
+	var validated []string
+	validate := func(_ context.Context, fpaths []string, p policy.Policy, _ bool) (*output2.Output, error) {
+		validated = append(validated, fpaths...)
+		assert.Equal(t, []string{"github.com/org/policy"}, p.Spec().Sources[0].Policy)
+
+		return &output2.Output{PolicyCheck: evaluator.CheckResults{{CheckResult: output.CheckResult{FileName: "/path/file1.yaml"}}}}, nil
+	}
+
+	cmd := validateInputCmd(validate)
+	cmd.SetContext(utils.WithFS(context.Background(), afero.NewMemMapFs()))
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	cmd.SetArgs([]string{
+		"--file",
+		"/path/file1.yaml",
+		"--policy",
+		`{"sources": [{"policy": ["github.com/org/policy"]}]}`,
+		"--effective-time",
+		"2022-11-23T16:30:00Z",
+	})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/path/file1.yaml"}, validated)
+
+	assert.JSONEq(t, `{
+		"success": true,
+		"filepaths": [
+			{
+				"filepath": "/path/file1.yaml",
+				"violations": [],
+				"warnings": [],
+				"success": true
+			}
+		],
+		"policy": {"sources": [{"policy": ["github.com/org/policy"]}]},
+		"ec-version": "development",
+		"effective-time": "2022-11-23T16:30:00Z"
+	}`, out.String())
+}
+
+func TestValidateInputCommandStdin(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	var content []byte
+	validate := func(_ context.Context, fpaths []string, _ policy.Policy, _ bool) (*output2.Output, error) {
+		require.Len(t, fpaths, 1)
+		var err error
+		content, err = afero.ReadFile(fs, fpaths[0])
+		require.NoError(t, err)
+
+		return &output2.Output{PolicyCheck: evaluator.CheckResults{{CheckResult: output.CheckResult{FileName: fpaths[0]}}}}, nil
+	}
+
+	cmd := validateInputCmd(validate)
+	cmd.SetContext(utils.WithFS(context.Background(), fs))
+	cmd.SetIn(strings.NewReader(`{"kind": "ReleasePlan"}`))
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	cmd.SetArgs([]string{
+		"--file",
+		"-",
+		"--policy",
+		`{"sources": []}`,
+	})
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"kind": "ReleasePlan"}`, string(content))
+
+	var report struct {
+		FilePaths []struct {
+			FilePath string `json:"filepath"`
+		} `json:"filepaths"`
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	require.Len(t, report.FilePaths, 1)
+	assert.Equal(t, "stdin", report.FilePaths[0].FilePath)
+
+	// the temporary file holding the document is removed
+	leftovers, err := afero.Glob(fs, filepath.Join(os.TempDir(), "input-*"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
+
+func TestValidateInputCommandErrors(t *testing.T) {
+	validate := func(context.Context, []string, policy.Policy, bool) (*output2.Output, error) {
+		return nil, errors.New("expected")
+	}
+
+	cmd := validateInputCmd(validate)
+	cmd.SetContext(utils.WithFS(context.Background(), afero.NewMemMapFs()))
+
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	cmd.SetArgs([]string{
+		"--file",
+		"/path/file1.yaml",
+		"--policy",
+		`{"sources": []}`,
+	})
+
+	err := cmd.Execute()
+	assert.EqualError(t, err, "error validating input /path/file1.yaml: expected")
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,76 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/enterprise-contract/ec-cli/internal/policy/source"
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+// resolvePolicyConfiguration returns the policy configuration with a git url
+// or a file path replaced by the content of the referenced configuration
+// file. Other values, i.e. inline configuration or a reference to the
+// EnterpriseContractPolicy resource, are returned as is.
+func resolvePolicyConfiguration(ctx context.Context, policyConfiguration string) (string, error) {
+	fs := utils.FS(ctx)
+
+	// Check if policyConfiguration is a git url, if so, try to download a config file from git
+	if source.SourceIsGit(policyConfiguration) {
+		log.Debugf("Fetching policy config from git url %s", policyConfiguration)
+
+		// Create a temporary dir to download the config. It will be different to the
+		// workdir used later for downloading policy sources, but it won't matter
+		// because this dir is not used again once the config file has been read.
+		tmpDir, err := utils.CreateWorkDir(fs)
+		if err != nil {
+			return "", err
+		}
+		defer utils.CleanupWorkDir(fs, tmpDir)
+
+		// Git download and find a suitable config file
+		configFile, err := source.GitConfigDownload(ctx, tmpDir, policyConfiguration)
+		if err != nil {
+			return "", err
+		}
+
+		// Changing policyConfiguration to the name of the newly downloaded
+		// file means we can use the code below to load the config
+		policyConfiguration = configFile
+	}
+
+	// Check if policyConfiguration is a file path, if so, we read it into policyConfiguration
+	if utils.HasJsonOrYamlExt(policyConfiguration) {
+		policyBytes, err := afero.ReadFile(fs, policyConfiguration)
+		if err != nil {
+			return "", err
+		}
+		// Check for empty file as that would cause a false "success"
+		if len(policyBytes) == 0 {
+			return "", fmt.Errorf("file %s is empty", policyConfiguration)
+		}
+
+		return string(policyBytes), nil
+	}
+
+	return policyConfiguration, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// progress reports the number of validated components and the names of the
+// components still being validated, a line is written on each change. When
+// redraw is set, e.g. when writing to a terminal, each line replaces the
+// previous one, otherwise the lines are newline terminated so they can be
+// followed in logs.
+type progress struct {
+	out        io.Writer
+	redraw     bool
+	total      int
+	done       int
+	inProgress map[string]int
+	mu         sync.Mutex
+}
+
+func newProgress(out io.Writer, total int, redraw bool) *progress {
+	return &progress{
+		out:        out,
+		redraw:     redraw,
+		total:      total,
+		inProgress: map[string]int{},
+	}
+}
+
+// start marks the named component as being validated
+func (p *progress) start(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.inProgress[name]++
+	p.report()
+}
+
+// finish marks the named component as validated
+func (p *progress) finish(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.inProgress[name]--; p.inProgress[name] <= 0 {
+		delete(p.inProgress, name)
+	}
+	p.done++
+	p.report()
+}
+
+func (p *progress) report() {
+	names := make([]string, 0, len(p.inProgress))
+	for name := range p.inProgress {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	line := fmt.Sprintf("Validated %d/%d components", p.done, p.total)
+	if len(names) > 0 {
+		line += fmt.Sprintf(", in progress: %s", strings.Join(names, ", "))
+	}
+
+	if !p.redraw {
+		fmt.Fprintln(p.out, line)
+		return
+	}
+
+	// return to the start of the line and clear it before writing the new
+	// one, the final line is kept
+	fmt.Fprintf(p.out, "\r\033[K%s", line)
+	if p.done == p.total {
+		fmt.Fprintln(p.out)
+	}
+}
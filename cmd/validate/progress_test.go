@@ -0,0 +1,61 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package validate
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgress(t *testing.T) {
+	var out bytes.Buffer
+	p := newProgress(&out, 3, false)
+
+	p.start("b")
+	p.start("a")
+	p.finish("b")
+	p.start("c")
+	p.finish("a")
+	p.finish("c")
+
+	assert.Equal(t, `Validated 0/3 components, in progress: b
+Validated 0/3 components, in progress: a, b
+Validated 1/3 components, in progress: a
+Validated 1/3 components, in progress: a, c
+Validated 2/3 components, in progress: c
+Validated 3/3 components
+`, out.String())
+}
+
+func TestProgressRedraw(t *testing.T) {
+	var out bytes.Buffer
+	p := newProgress(&out, 2, true)
+
+	p.start("a")
+	p.finish("a")
+	p.start("b")
+	p.finish("b")
+
+	assert.Equal(t, "\r\033[KValidated 0/2 components, in progress: a"+
+		"\r\033[KValidated 1/2 components"+
+		"\r\033[KValidated 1/2 components, in progress: b"+
+		"\r\033[KValidated 2/2 components\n", out.String())
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package validate
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/hashicorp/go-multierror"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/enterprise-contract/ec-cli/internal/applicationsnapshot"
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+	"github.com/enterprise-contract/ec-cli/internal/vsa"
+)
+
+var invalidFileNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// vsaOptions control the creation of Verification Summary Attestations
+type vsaOptions struct {
+	signingKey     string
+	outputDir      string
+	upload         bool
+	verifiedLevels []string
+	policyURI      string
+	trustedKey     string
+	maxAge         time.Duration
+	checker        *vsa.Checker
+}
+
+// createVSAs creates a signed Verification Summary Attestation for each
+// component in the report, writing it to the output directory and/or
+// uploading it to the registry as an attestation of the component's image.
+func createVSAs(ctx context.Context, report applicationsnapshot.Report, opts vsaOptions) (allErrors error) {
+	signer, err := vsa.NewSigner(ctx, opts.signingKey)
+	if err != nil {
+		return fmt.Errorf("unable to load the VSA signing key: %w", err)
+	}
+
+	fs := utils.FS(ctx)
+	if opts.outputDir != "" {
+		if err := fs.MkdirAll(opts.outputDir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range report.Components {
+		if err := createVSA(ctx, fs, signer, report, c, opts); err != nil {
+			allErrors = multierror.Append(allErrors, fmt.Errorf("unable to create the VSA of component %s: %w", c.Name, err))
+		}
+	}
+
+	return
+}
+
+func createVSA(ctx context.Context, fs afero.Fs, signer *vsa.Signer, report applicationsnapshot.Report, c applicationsnapshot.Component, opts vsaOptions) error {
+	ref, err := name.NewDigest(c.ContainerImage)
+	if err != nil {
+		return fmt.Errorf("the image reference must include a digest: %w", err)
+	}
+
+	predicate, err := report.VSAPredicate(c, opts.policyURI, opts.verifiedLevels)
+	if err != nil {
+		return err
+	}
+
+	envelope, err := signer.Sign(ctx, vsa.NewStatement(ref, predicate))
+	if err != nil {
+		return err
+	}
+
+	if opts.outputDir != "" {
+		file := filepath.Join(opts.outputDir, invalidFileNameChars.ReplaceAllString(c.Name, "-")+".vsa.json")
+		if err := afero.WriteFile(fs, file, envelope, 0o644); err != nil {
+			return err
+		}
+		log.Debugf("Wrote VSA of component %s to %s", c.Name, file)
+	}
+
+	if opts.upload {
+		if err := vsa.Attach(ctx, ref, envelope); err != nil {
+			return err
+		}
+		log.Debugf("Uploaded VSA of component %s to %s", c.Name, ref.Repository)
+	}
+
+	return nil
+}
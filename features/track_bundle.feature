@@ -42,7 +42,7 @@ Feature: track bundles
     When a tekton bundle image named "acceptance/bundle:1.1" containing
       | Task     | task2     |
       | Pipeline | pipeline2 |
-    When ec command is run with "track bundle --bundle ${REGISTRY}/acceptance/bundle:1.1 --input oci:${REGISTRY}/tracked/bundle:tag --replace"
+    When ec command is run with "track bundle --bundle ${REGISTRY}/acceptance/bundle:1.1 --input oci:${REGISTRY}/tracked/bundle:tag --replace --insecure-skip-verify"
     Then the exit status should be 0
     Then registry image "tracked/bundle:tag" should contain a layer with
     """
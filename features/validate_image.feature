@@ -164,7 +164,7 @@ Feature: evaluate enterprise contract
     Then the exit status should be 0
     Then the output should match the snapshot
 
-  Scenario: future failure is converted to a warning
+  Scenario: future failure is reported as a future violation
     Given a key pair named "known"
     Given an image named "acceptance/ec-happy-day"
     Given a valid image signature of "acceptance/ec-happy-day" image signed by the "known" key
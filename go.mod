@@ -32,6 +32,7 @@ require (
 	github.com/tektoncd/pipeline v0.47.0
 	golang.org/x/exp v0.0.0-20230522175609-2e198f4a06a1
 	golang.org/x/net v0.11.0
+	golang.org/x/term v0.9.0
 	golang.org/x/tools v0.10.0
 	k8s.io/apimachinery v0.27.3
 	k8s.io/client-go v0.26.3
@@ -277,7 +278,6 @@ require (
 	golang.org/x/oauth2 v0.9.0 // indirect
 	golang.org/x/sync v0.3.0 // indirect
 	golang.org/x/sys v0.9.0 // indirect
-	golang.org/x/term v0.9.0 // indirect
 	golang.org/x/text v0.10.0 // indirect
 	golang.org/x/time v0.3.0 // indirect
 	golang.org/x/xerrors v0.0.0-20220907171357-04be3eba64a2 // indirect
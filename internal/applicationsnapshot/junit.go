@@ -23,44 +23,9 @@ import (
 
 	"cuelang.org/go/pkg/time"
 	"github.com/jstemmer/go-junit-report/v2/junit"
-	conftestOutput "github.com/open-policy-agent/conftest/output"
-	"golang.org/x/exp/maps"
-)
-
-// mapResults maps an slice of Conftest results to a slice of arbitrary types
-// given a mapper function
-func mapResults(suite *junit.Testsuite, results []conftestOutput.Result, m func(conftestOutput.Result) junit.Testcase) {
-	for _, r := range results {
-		suite.AddTestcase(m(r))
-	}
-}
-
-func asTestCase(r conftestOutput.Result) junit.Testcase {
-	meta := maps.Clone(r.Metadata)
-	delete(meta, "code")
 
-	metaDesc := make([]string, 0, 3)
-	for k, v := range meta {
-		metaDesc = append(metaDesc, fmt.Sprintf("%s=%v", k, v))
-	}
-
-	desc := ""
-	if len(metaDesc) > 0 {
-		sort.Strings(metaDesc)
-		desc = " [" + strings.Join(metaDesc, ", ") + "]"
-	}
-
-	name := fmt.Sprintf("%s%s", r.Message, desc)
-
-	if code, ok := r.Metadata["code"].(string); ok {
-		name = fmt.Sprintf("%s: %s", code, name)
-	}
-
-	return junit.Testcase{
-		Name:      name,
-		Classname: name, // some reporting tools might require Classname as well
-	}
-}
+	"github.com/enterprise-contract/ec-cli/internal/format"
+)
 
 // toJUnit returns a version of the report in JUnit XML format
 func (r *Report) toJUnit() junit.Testsuites {
@@ -110,27 +75,8 @@ func (r *Report) toJUnit() junit.Testsuites {
 			Properties: &properties,
 		}
 
-		mapResults(&suite, component.Successes, asTestCase)
-
-		mapResults(&suite, component.Violations, func(r conftestOutput.Result) junit.Testcase {
-			c := asTestCase(r)
-			c.Failure = &junit.Result{
-				Message: r.Message,
-				Data:    r.Message,
-			}
-
-			return c
-		})
-
-		mapResults(&suite, component.Warnings, func(r conftestOutput.Result) junit.Testcase {
-			c := asTestCase(r)
-			c.Skipped = &junit.Result{
-				Message: r.Message,
-				Data:    r.Message,
-			}
-
-			return c
-		})
+		format.AddJUnitTestcases(&suite, component.Successes, component.Violations,
+			component.Warnings, component.FutureViolations, component.Exempted)
 
 		report.AddSuite(suite)
 	}
@@ -27,66 +27,6 @@ import (
 	o "github.com/enterprise-contract/ec-cli/internal/output"
 )
 
-func TestMapResults(t *testing.T) {
-	s := junit.Testsuite{}
-	mapResults(&s, []output.Result{{Message: "0"}, {Message: "1"}, {Message: "2"}}, func(r output.Result) junit.Testcase {
-		return junit.Testcase{
-			Name: r.Message,
-		}
-	})
-
-	assert.Equal(t, junit.Testsuite{
-		Tests: 3,
-		Testcases: []junit.Testcase{
-			{Name: "0"},
-			{Name: "1"},
-			{Name: "2"},
-		},
-	}, s)
-
-}
-
-func TestAsTestCase(t *testing.T) {
-	cases := []struct {
-		name     string
-		result   output.Result
-		expected junit.Testcase
-	}{
-		{
-			name:     "nil",
-			expected: junit.Testcase{},
-		},
-		{
-			name:     "trivial",
-			result:   output.Result{Message: "msg"},
-			expected: junit.Testcase{Name: "msg", Classname: "msg"},
-		},
-		{
-			name:     "with code",
-			result:   output.Result{Message: "msg", Metadata: map[string]interface{}{"code": "a.b.c"}},
-			expected: junit.Testcase{Name: "a.b.c: msg", Classname: "a.b.c: msg"},
-		},
-		{
-			name:     "with metadata",
-			result:   output.Result{Message: "msg", Metadata: map[string]interface{}{"x": "1", "y": "2", "z": "3"}},
-			expected: junit.Testcase{Name: "msg [x=1, y=2, z=3]", Classname: "msg [x=1, y=2, z=3]"},
-		},
-		{
-			name:     "with code and metadata",
-			result:   output.Result{Message: "msg", Metadata: map[string]interface{}{"code": "a.b.c", "x": "1", "y": "2", "z": "3"}},
-			expected: junit.Testcase{Name: "a.b.c: msg [x=1, y=2, z=3]", Classname: "a.b.c: msg [x=1, y=2, z=3]"},
-		},
-	}
-
-	for _, c := range cases {
-		t.Run(c.name, func(t *testing.T) {
-			got := asTestCase(c.result)
-
-			assert.Equal(t, c.expected, got)
-		})
-	}
-}
-
 func TestToJunit(t *testing.T) {
 	cases := []struct {
 		name     string
@@ -239,3 +179,34 @@ func TestToJunit(t *testing.T) {
 		})
 	}
 }
+
+func TestToJunitFutureViolations(t *testing.T) {
+	report := Report{
+		Components: []Component{
+			{
+				SnapshotComponent: v1alpha1.SnapshotComponent{
+					Name:           "Name",
+					ContainerImage: "registry.io/repository/image:tag",
+				},
+				FutureViolations: []output.Result{
+					{
+						Message: "future",
+						Metadata: map[string]interface{}{
+							"code":         "future",
+							"effective_on": "2099-01-01T00:00:00Z",
+						},
+					},
+				},
+				Success: true,
+			},
+		},
+	}
+
+	suites := report.toJUnit()
+	assert.Equal(t, 1, suites.Skipped)
+	assert.Equal(t, 0, suites.Failures)
+
+	testcase := suites.Suites[0].Testcases[0]
+	assert.Equal(t, "future: future [effective_on=2099-01-01T00:00:00Z]", testcase.Name)
+	assert.Equal(t, "Future violation, enforced from 2099-01-01T00:00:00Z: future", testcase.Skipped.Message)
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package applicationsnapshot
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	conftestOutput "github.com/open-policy-agent/conftest/output"
+)
+
+// markdownEscaper escapes text so it can be placed within a table cell or a
+// list item without breaking the Markdown structure
+var markdownEscaper = strings.NewReplacer("|", `\|`, "`", "\\`", "\r\n", " ", "\n", " ")
+
+// toMarkdown returns a version of the report in Markdown, e.g. to be posted
+// as a pull request comment, with a summary table of the components followed
+// by collapsible sections listing the results of each component
+func (r *Report) toMarkdown() []byte {
+	buf := bytes.Buffer{}
+
+	status := ":white_check_mark: Success"
+	if !r.Success {
+		status = ":x: Failure"
+	}
+	fmt.Fprintf(&buf, "## Enterprise Contract: %s\n\n", status)
+
+	buf.WriteString("| Component | Image | Result | Violations | Warnings | Successes |\n")
+	buf.WriteString("|---|---|---|---:|---:|---:|\n")
+	for _, c := range r.Components {
+		fmt.Fprintf(&buf, "| %s | `%s` | %s | %d | %d | %d |\n",
+			markdownEscaper.Replace(c.Name), markdownEscaper.Replace(c.ContainerImage),
+			markdownStatus(c), len(c.Violations), len(c.Warnings), len(c.Successes))
+	}
+
+	for _, c := range r.Components {
+		if len(c.Violations)+len(c.Warnings)+len(c.Successes) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&buf, "\n### %s\n", c.Name)
+		writeMarkdownResults(&buf, "Violations", c.Violations, c.Rules)
+		writeMarkdownResults(&buf, "Warnings", c.Warnings, c.Rules)
+		writeMarkdownResults(&buf, "Successes", c.Successes, c.Rules)
+	}
+
+	return buf.Bytes()
+}
+
+func markdownStatus(c Component) string {
+	switch {
+	case !c.Success:
+		return ":x:"
+	case len(c.Warnings) > 0:
+		return ":warning:"
+	default:
+		return ":white_check_mark:"
+	}
+}
+
+// writeMarkdownResults writes the results as a list within a collapsible
+// section, the code of each result is linked to the documentation of the rule
+// when known from the metadata of the rules
+func writeMarkdownResults(buf *bytes.Buffer, title string, results []conftestOutput.Result, rules map[string]map[string]interface{}) {
+	if len(results) == 0 {
+		return
+	}
+
+	fmt.Fprintf(buf, "\n<details>\n<summary>%s (%d)</summary>\n\n", title, len(results))
+	for _, r := range results {
+		code, _ := r.Metadata["code"].(string)
+		url, _ := rules[code]["documentation_url"].(string)
+		message := markdownEscaper.Replace(r.Message)
+
+		switch {
+		case code != "" && url != "":
+			fmt.Fprintf(buf, "- [`%s`](%s): %s\n", code, url, message)
+		case code != "":
+			fmt.Fprintf(buf, "- `%s`: %s\n", code, message)
+		default:
+			fmt.Fprintf(buf, "- %s\n", message)
+		}
+	}
+	buf.WriteString("\n</details>\n")
+}
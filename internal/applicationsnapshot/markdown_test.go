@@ -0,0 +1,98 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package applicationsnapshot
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/open-policy-agent/conftest/output"
+	app "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ReportMarkdown(t *testing.T) {
+	r := Report{
+		Success: false,
+		Components: []Component{
+			{
+				SnapshotComponent: app.SnapshotComponent{
+					Name:           "spam",
+					ContainerImage: "quay.io/caf/spam@sha256:123",
+				},
+				Violations: []output.Result{
+					{
+						Message:  "Missing required task",
+						Metadata: map[string]interface{}{"code": "tasks.required"},
+					},
+				},
+				Warnings: []output.Result{
+					{
+						Message:  "Deprecated task",
+						Metadata: map[string]interface{}{"code": "tasks.deprecated"},
+					},
+					{Message: "No code, `x | y`\nspans lines"},
+				},
+				Rules: map[string]map[string]interface{}{
+					"tasks.required": {
+						"code":              "tasks.required",
+						"documentation_url": "https://enterprisecontract.dev/docs/ec-policies/release_policy.html#tasks__required",
+					},
+				},
+			},
+			{
+				SnapshotComponent: app.SnapshotComponent{
+					Name:           "eggs | bacon",
+					ContainerImage: "quay.io/caf/eggs@sha256:345",
+				},
+				Success: true,
+			},
+		},
+	}
+
+	data, err := r.toFormat(MARKDOWN)
+	require.NoError(t, err)
+
+	assert.Equal(t, heredoc.Doc(`
+		## Enterprise Contract: :x: Failure
+
+		| Component | Image | Result | Violations | Warnings | Successes |
+		|---|---|---|---:|---:|---:|
+		| spam | `+"`quay.io/caf/spam@sha256:123`"+` | :x: | 1 | 2 | 0 |
+		| eggs \| bacon | `+"`quay.io/caf/eggs@sha256:345`"+` | :white_check_mark: | 0 | 0 | 0 |
+
+		### spam
+
+		<details>
+		<summary>Violations (1)</summary>
+
+		- [`+"`tasks.required`"+`](https://enterprisecontract.dev/docs/ec-policies/release_policy.html#tasks__required): Missing required task
+
+		</details>
+
+		<details>
+		<summary>Warnings (2)</summary>
+
+		- `+"`tasks.deprecated`"+`: Deprecated task
+		- No code, \`+"`"+`x \| y\`+"`"+` spans lines
+
+		</details>
+	`), string(data))
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"strings"
 	"time"
 
 	ecc "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
@@ -41,6 +42,15 @@ type Component struct {
 	Successes  []conftestOutput.Result  `json:"successes,omitempty"`
 	Success    bool                     `json:"success"`
 	Signatures []output.EntitySignature `json:"signatures,omitempty"`
+	// FutureViolations are violations of rules that are not yet effective,
+	// the effective_on metadata holds the date each becomes enforced
+	FutureViolations []conftestOutput.Result `json:"futureViolations,omitempty"`
+	// Exempted are violations and warnings covered by a policy exception,
+	// the exception metadata holds its details
+	Exempted []conftestOutput.Result `json:"exempted,omitempty"`
+	// Rules holds the metadata of the rules of the results, keyed by the rule
+	// code, regardless of the metadata kept in the results
+	Rules map[string]map[string]interface{} `json:"-"`
 }
 
 type Report struct {
@@ -53,6 +63,9 @@ type Report struct {
 	EcVersion     string                           `json:"ec-version"`
 	Data          any                              `json:"-"`
 	EffectiveTime time.Time                        `json:"effective-time"`
+	// policy is the policy the components were validated against, including
+	// the exceptions and overrides not held in the Policy specification
+	policy policy.Policy
 }
 
 type summary struct {
@@ -63,14 +76,18 @@ type summary struct {
 }
 
 type componentSummary struct {
-	Name            string              `json:"name"`
-	Success         bool                `json:"success"`
-	Violations      map[string][]string `json:"violations"`
-	Warnings        map[string][]string `json:"warnings"`
-	Successes       map[string][]string `json:"successes"`
-	TotalViolations int                 `json:"total_violations"`
-	TotalWarnings   int                 `json:"total_warnings"`
-	TotalSuccesses  int                 `json:"total_successes"`
+	Name                  string              `json:"name"`
+	Success               bool                `json:"success"`
+	Violations            map[string][]string `json:"violations"`
+	Warnings              map[string][]string `json:"warnings"`
+	Successes             map[string][]string `json:"successes"`
+	FutureViolations      map[string][]string `json:"future_violations,omitempty"`
+	Exempted              map[string][]string `json:"exempted,omitempty"`
+	TotalViolations       int                 `json:"total_violations"`
+	TotalWarnings         int                 `json:"total_warnings"`
+	TotalSuccesses        int                 `json:"total_successes"`
+	TotalFutureViolations int                 `json:"total_future_violations,omitempty"`
+	TotalExempted         int                 `json:"total_exempted,omitempty"`
 }
 
 // testReport represents the standardized TEST_OUTPUT format.
@@ -78,12 +95,13 @@ type componentSummary struct {
 // it is always an empty string from the ec-cli as a way to indicate all
 // namespaces were used.
 type testReport struct {
-	Timestamp string `json:"timestamp"`
-	Namespace string `json:"namespace"`
-	Successes int    `json:"successes"`
-	Failures  int    `json:"failures"`
-	Warnings  int    `json:"warnings"`
-	Result    string `json:"result"`
+	Timestamp        string `json:"timestamp"`
+	Namespace        string `json:"namespace"`
+	Successes        int    `json:"successes"`
+	Failures         int    `json:"failures"`
+	Warnings         int    `json:"warnings"`
+	FutureViolations int    `json:"future_violations,omitempty"`
+	Result           string `json:"result"`
 }
 
 // Possible formats the report can be written as.
@@ -92,10 +110,16 @@ const (
 	YAML      = "yaml"
 	APPSTUDIO = "appstudio"
 	// Deprecated. Remove when hacbs output is removed
-	HACBS   = "hacbs"
-	Summary = "summary"
-	JUNIT   = "junit"
-	DATA    = "data"
+	HACBS    = "hacbs"
+	Summary  = "summary"
+	JUNIT    = "junit"
+	DATA     = "data"
+	SARIF    = "sarif"
+	TEXT     = "text"
+	MARKDOWN = "markdown"
+	// TEMPLATE is followed by the path of the Go template file used to
+	// render the report, e.g. template:/path/to/report.tmpl
+	TEMPLATE = "template"
 )
 
 // WriteReport returns a new instance of Report representing the state of
@@ -130,6 +154,7 @@ func NewReport(snapshot string, components []Component, policy policy.Policy, da
 		EcVersion:     info.Version,
 		Data:          data,
 		EffectiveTime: policy.EffectiveTime().UTC(),
+		policy:        policy,
 	}, nil
 }
 
@@ -141,7 +166,7 @@ func (r Report) WriteAll(targets []string, p format.TargetParser) (allErrors err
 	for _, targetName := range targets {
 		target := p.Parse(targetName)
 
-		if data, err := r.toFormat(target.Format); err != nil {
+		if data, err := r.render(target, p); err != nil {
 			allErrors = multierror.Append(allErrors, err)
 		} else if _, err := target.Write(data); err != nil {
 			allErrors = multierror.Append(allErrors, err)
@@ -150,6 +175,22 @@ func (r Report) WriteAll(targets []string, p format.TargetParser) (allErrors err
 	return
 }
 
+// render converts the report into the format of the target, handling the
+// formats that depend on the target or require additional input before
+// deferring to toFormat.
+func (r *Report) render(target format.Target, p format.TargetParser) ([]byte, error) {
+	if target.Format == TEXT {
+		// colored only when written to a terminal
+		return r.toText(target.IsTerminal()), nil
+	}
+
+	if name, path, ok := strings.Cut(target.Format, ":"); ok && name == TEMPLATE {
+		return r.toTemplate(p.FS(), path)
+	}
+
+	return r.toFormat(target.Format)
+}
+
 // toFormat converts the report into the given format.
 func (r *Report) toFormat(format string) (data []byte, err error) {
 	switch format {
@@ -166,6 +207,10 @@ func (r *Report) toFormat(format string) (data []byte, err error) {
 		data, err = json.Marshal(r.toAppstudioReport())
 	case JUNIT:
 		data, err = xml.Marshal(r.toJUnit())
+	case SARIF:
+		data, err = json.Marshal(r.toSARIF())
+	case MARKDOWN:
+		data = r.toMarkdown()
 	case DATA:
 		data, err = yaml.Marshal(r.Data)
 	default:
@@ -189,9 +234,17 @@ func (r *Report) toSummary() summary {
 			TotalSuccesses:  len(cmp.Successes),
 			Success:         cmp.Success,
 			Name:            cmp.Name,
-			Violations:      condensedMsg(cmp.Violations),
-			Warnings:        condensedMsg(cmp.Warnings),
-			Successes:       condensedMsg(cmp.Successes),
+			Violations:      format.CondensedMessages(cmp.Violations),
+			Warnings:        format.CondensedMessages(cmp.Warnings),
+			Successes:       format.CondensedMessages(cmp.Successes),
+		}
+		if len(cmp.FutureViolations) > 0 {
+			c.TotalFutureViolations = len(cmp.FutureViolations)
+			c.FutureViolations = format.CondensedMessages(format.WithEnforcementDate(cmp.FutureViolations))
+		}
+		if len(cmp.Exempted) > 0 {
+			c.TotalExempted = len(cmp.Exempted)
+			c.Exempted = format.CondensedMessages(format.WithExceptionExpiry(cmp.Exempted))
 		}
 		pr.Components = append(pr.Components, c)
 	}
@@ -199,30 +252,6 @@ func (r *Report) toSummary() summary {
 	return pr
 }
 
-// condensedMsg reduces repetitive error messages.
-func condensedMsg(results []conftestOutput.Result) map[string][]string {
-	maxErr := 1
-	shortNames := make(map[string][]string)
-	count := make(map[string]int)
-	for _, v := range results {
-		code, isPresent := v.Metadata["code"]
-		// we don't want to keep count of the empty string
-		if isPresent {
-			code := fmt.Sprintf("%v", code)
-			if count[code] < maxErr {
-				shortNames[code] = append(shortNames[code], v.Message)
-			}
-			count[code] = count[code] + 1
-		}
-	}
-	for k := range shortNames {
-		if count[k] > maxErr {
-			shortNames[k] = append(shortNames[k], fmt.Sprintf("There are %v more %q messages", count[k]-1, k))
-		}
-	}
-	return shortNames
-}
-
 // toAppstudioReport returns a version of the report that conforms to the
 // TEST_OUTPUT format.
 // (Note: the name of the Tekton task result where this generally
@@ -234,6 +263,7 @@ func (r *Report) toAppstudioReport() testReport {
 	for _, component := range r.Components {
 		result.Failures += len(component.Violations)
 		result.Warnings += len(component.Warnings)
+		result.FutureViolations += len(component.FutureViolations)
 		if component.Success {
 			result.Successes += 1
 		} else {
@@ -246,7 +276,7 @@ func (r *Report) toAppstudioReport() testReport {
 	switch {
 	case result.Failures > 0 || hasFailures:
 		result.Result = "FAILURE"
-	case result.Warnings > 0:
+	case result.Warnings > 0 || result.FutureViolations > 0:
 		result.Result = "WARNING"
 	case result.Successes == 0:
 		result.Result = "SKIPPED"
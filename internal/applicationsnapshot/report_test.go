@@ -194,6 +194,68 @@ func Test_ReportSummary(t *testing.T) {
 				Key:     utils.TestPublicKey,
 			},
 		},
+		{
+			name: "testing future violation",
+			input: Component{
+				FutureViolations: []output.Result{
+					{
+						Message: "future report",
+						Metadata: map[string]interface{}{
+							"code":         "future_name",
+							"effective_on": "2099-01-01T00:00:00Z",
+						},
+					},
+				},
+				Success: true,
+			},
+			want: summary{
+				Components: []componentSummary{
+					{
+						Violations: map[string][]string{},
+						Warnings:   map[string][]string{},
+						Successes:  map[string][]string{},
+						FutureViolations: map[string][]string{
+							"future_name": {"future report (enforced from 2099-01-01T00:00:00Z)"},
+						},
+						TotalFutureViolations: 1,
+						Success:               true,
+					},
+				},
+				Key: utils.TestPublicKey,
+			},
+		},
+		{
+			name: "testing exempted",
+			input: Component{
+				Exempted: []output.Result{
+					{
+						Message: "exempted report",
+						Metadata: map[string]interface{}{
+							"code": "exempted_name",
+							"exception": map[string]interface{}{
+								"expires": "2099-01-01",
+							},
+						},
+					},
+				},
+				Success: true,
+			},
+			want: summary{
+				Components: []componentSummary{
+					{
+						Violations: map[string][]string{},
+						Warnings:   map[string][]string{},
+						Successes:  map[string][]string{},
+						Exempted: map[string][]string{
+							"exempted_name": {"exempted report (exempted until 2099-01-01)"},
+						},
+						TotalExempted: 1,
+						Success:       true,
+					},
+				},
+				Key: utils.TestPublicKey,
+			},
+		},
 		{
 			name: "testing no metadata",
 			input: Component{
@@ -426,6 +488,23 @@ func Test_ReportAppstudio(t *testing.T) {
 			},
 			success: true,
 		},
+		{
+			name: "future violation",
+			expected: `
+			{
+				"failures": 0,
+				"future_violations": 1,
+				"namespace": "",
+				"result": "WARNING",
+				"successes": 1,
+				"timestamp": "0",
+				"warnings": 0
+			}`,
+			components: []Component{
+				{Success: true, FutureViolations: []output.Result{{Message: "this will be a violation"}}},
+			},
+			success: true,
+		},
 		{
 			name: "failure",
 			expected: `
@@ -0,0 +1,37 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package applicationsnapshot
+
+import (
+	"github.com/enterprise-contract/ec-cli/internal/sarif"
+)
+
+// toSARIF returns a version of the report in the SARIF format, with the
+// images of the components as the artifacts. Future violations and exempted
+// results are reported as notes.
+func (r *Report) toSARIF() sarif.Log {
+	b := sarif.NewBuilder(r.EcVersion)
+	for _, component := range r.Components {
+		b.AddRuleMetadata(component.Rules)
+		b.Add(component.ContainerImage, component.Name, sarif.LevelError, component.Violations)
+		b.Add(component.ContainerImage, component.Name, sarif.LevelWarning, component.Warnings)
+		b.Add(component.ContainerImage, component.Name, sarif.LevelNote, component.FutureViolations)
+		b.Add(component.ContainerImage, component.Name, sarif.LevelNote, component.Exempted)
+	}
+
+	return b.Log()
+}
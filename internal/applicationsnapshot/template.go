@@ -0,0 +1,124 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package applicationsnapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	conftestOutput "github.com/open-policy-agent/conftest/output"
+	"github.com/spf13/afero"
+)
+
+// templateFuncs are the helper functions available to report templates
+var templateFuncs = template.FuncMap{
+	"results":     results,
+	"groupByCode": groupByCode,
+	"toJSON":      toJSON,
+	"join":        strings.Join,
+	"upper":       strings.ToUpper,
+	"lower":       strings.ToLower,
+}
+
+// toTemplate renders the report using the Go template in the file at the
+// given path
+func (r *Report) toTemplate(fs afero.Fs, path string) ([]byte, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the %s format requires the path to the template, e.g. %s:report.tmpl", TEMPLATE, TEMPLATE)
+	}
+
+	text, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read report template: %w", err)
+	}
+
+	t, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Parse(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse report template: %w", err)
+	}
+
+	buf := bytes.Buffer{}
+	if err := t.Execute(&buf, r); err != nil {
+		return nil, fmt.Errorf("unable to render report template: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// results returns the results of the given severity, i.e. violation,
+// warning, success, future_violation or exempted, of the component, or of all
+// components of the report
+func results(severity string, from any) ([]conftestOutput.Result, error) {
+	var components []Component
+	switch f := from.(type) {
+	case Component:
+		components = []Component{f}
+	case *Component:
+		components = []Component{*f}
+	case Report:
+		components = f.Components
+	case *Report:
+		components = f.Components
+	default:
+		return nil, fmt.Errorf("results expects a component or a report, got: %T", from)
+	}
+
+	found := []conftestOutput.Result{}
+	for _, c := range components {
+		switch severity {
+		case "violation":
+			found = append(found, c.Violations...)
+		case "warning":
+			found = append(found, c.Warnings...)
+		case "success":
+			found = append(found, c.Successes...)
+		case "future_violation":
+			found = append(found, c.FutureViolations...)
+		case "exempted":
+			found = append(found, c.Exempted...)
+		default:
+			return nil, fmt.Errorf("unknown severity %q, expecting one of: violation, warning, success, future_violation or exempted", severity)
+		}
+	}
+
+	return found, nil
+}
+
+// groupByCode groups the results by the code in their metadata, results
+// without a code are grouped under the empty string
+func groupByCode(results []conftestOutput.Result) map[string][]conftestOutput.Result {
+	grouped := map[string][]conftestOutput.Result{}
+	for _, r := range results {
+		code, _ := r.Metadata["code"].(string)
+		grouped[code] = append(grouped[code], r)
+	}
+
+	return grouped
+}
+
+func toJSON(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package applicationsnapshot
+
+import (
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/open-policy-agent/conftest/output"
+	app "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/format"
+)
+
+func Test_ReportTemplate(t *testing.T) {
+	r := Report{
+		Success: false,
+		Components: []Component{
+			{
+				SnapshotComponent: app.SnapshotComponent{Name: "spam"},
+				Violations: []output.Result{
+					{Message: "violation1", Metadata: map[string]interface{}{"code": "a.one"}},
+					{Message: "violation2", Metadata: map[string]interface{}{"code": "a.one"}},
+					{Message: "violation3", Metadata: map[string]interface{}{"code": "b.two"}},
+				},
+				Warnings: []output.Result{
+					{Message: "warning1", Metadata: map[string]interface{}{"code": "c.three"}},
+				},
+			},
+			{
+				SnapshotComponent: app.SnapshotComponent{Name: "eggs"},
+				Warnings: []output.Result{
+					{Message: "warning2", Metadata: map[string]interface{}{"code": "c.three"}},
+				},
+				Success: true,
+			},
+		},
+	}
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "report.tmpl", []byte(heredoc.Doc(`
+		{{ range .Components -}}
+		{{ upper .Name }}: {{ .Success }}
+		{{ range $code, $results := groupByCode (results "violation" .) -}}
+		{{ "  " }}{{ $code }}: {{ len $results }}
+		{{ end -}}
+		{{ end -}}
+		warnings: {{ toJSON (results "warning" $) }}
+	`)), 0644))
+
+	require.NoError(t, r.WriteAll([]string{"template:report.tmpl=report.txt"}, format.NewTargetParser(JSON, nil, fs)))
+
+	text, err := afero.ReadFile(fs, "report.txt")
+	require.NoError(t, err)
+	assert.Equal(t, heredoc.Doc(`
+		SPAM: false
+		  a.one: 2
+		  b.two: 1
+		EGGS: true
+		warnings: [{"msg":"warning1","metadata":{"code":"c.three"}},{"msg":"warning2","metadata":{"code":"c.three"}}]
+	`), string(text))
+}
+
+func Test_ReportTemplateErrors(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "invalid.tmpl", []byte(`{{ .Nope`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "severity.tmpl", []byte(`{{ results "fatal" . }}`), 0644))
+
+	p := format.NewTargetParser(JSON, nil, fs)
+	r := Report{}
+
+	assert.ErrorContains(t, r.WriteAll([]string{"template:=report.txt"}, p), "requires the path to the template")
+	assert.ErrorContains(t, r.WriteAll([]string{"template:missing.tmpl=report.txt"}, p), "unable to read report template")
+	assert.ErrorContains(t, r.WriteAll([]string{"template:invalid.tmpl=report.txt"}, p), "unable to parse report template")
+	assert.ErrorContains(t, r.WriteAll([]string{"template:severity.tmpl=report.txt"}, p), `unknown severity "fatal"`)
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package applicationsnapshot
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/enterprise-contract/ec-cli/internal/format"
+)
+
+// toText returns a human readable version of the report, grouped by
+// component, colored if color is true
+func (r *Report) toText(color bool) []byte {
+	p := format.NewPainter(color)
+	buf := bytes.Buffer{}
+
+	var violations, warnings, successes int
+	for _, c := range r.Components {
+		violations += len(c.Violations)
+		warnings += len(c.Warnings)
+		successes += len(c.Successes)
+	}
+
+	fmt.Fprintf(&buf, "%s %s\n", p.Bold("Success:"), p.Status(r.Success, warnings))
+	fmt.Fprintf(&buf, "Components: %d, Violations: %s, Warnings: %s, Successes: %s\n",
+		len(r.Components), p.Red(fmt.Sprint(violations)), p.Yellow(fmt.Sprint(warnings)), p.Green(fmt.Sprint(successes)))
+
+	for _, c := range r.Components {
+		buf.WriteString("\n")
+		fmt.Fprintf(&buf, "%s %s\n", p.Bold("Component:"), c.Name)
+		fmt.Fprintf(&buf, "Image: %s\n", c.ContainerImage)
+		fmt.Fprintf(&buf, "Result: %s (violations: %d, warnings: %d, successes: %d)\n",
+			p.Status(c.Success, len(c.Warnings)), len(c.Violations), len(c.Warnings), len(c.Successes))
+
+		format.WriteTextResults(&buf, "Violation", p.Red, c.Violations)
+		format.WriteTextResults(&buf, "Warning", p.Yellow, c.Warnings)
+		format.WriteTextResults(&buf, "Future violation", p.Yellow, format.WithEnforcementDate(c.FutureViolations))
+		format.WriteTextResults(&buf, "Exempted", p.Faint, format.WithExceptionExpiry(c.Exempted))
+		format.WriteTextResults(&buf, "Success", p.Green, c.Successes)
+	}
+
+	return buf.Bytes()
+}
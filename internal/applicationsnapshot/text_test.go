@@ -0,0 +1,77 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package applicationsnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	app "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/format"
+)
+
+func Test_ReportText(t *testing.T) {
+	var snapshot app.SnapshotSpec
+	require.NoError(t, json.Unmarshal([]byte(testSnapshot), &snapshot))
+
+	components := testComponentsFor(snapshot)
+
+	report, err := NewReport("snappy", components, createTestPolicy(t, context.Background()), nil)
+	require.NoError(t, err)
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, report.WriteAll([]string{"text=report.txt"}, format.NewTargetParser(JSON, nil, fs)))
+
+	text, err := afero.ReadFile(fs, "report.txt")
+	require.NoError(t, err)
+
+	// not written to a terminal, so no colors
+	assert.Equal(t, heredoc.Doc(`
+		Success: FAIL
+		Components: 3, Violations: 2, Warnings: 1, Successes: 2
+
+		Component: spam
+		Image: quay.io/caf/spam@sha256:123…
+		Result: FAIL (violations: 1, warnings: 1, successes: 1)
+		  Violation
+		    violation1
+		  Warning
+		    warning1
+		  Success
+		    success1
+
+		Component: bacon
+		Image: quay.io/caf/bacon@sha256:234…
+		Result: FAIL (violations: 1, warnings: 0, successes: 0)
+		  Violation
+		    violation2
+
+		Component: eggs
+		Image: quay.io/caf/eggs@sha256:345…
+		Result: PASS (violations: 0, warnings: 0, successes: 1)
+		  Success
+		    success3
+	`), string(text))
+}
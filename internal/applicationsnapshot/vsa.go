@@ -0,0 +1,75 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package applicationsnapshot
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/enterprise-contract/ec-cli/internal/vsa"
+)
+
+// VSAPredicate returns the predicate of the Verification Summary Attestation
+// of the component, identifying the policy by its URI, if provided, and the
+// digest of the policy as resolved for the component. The verified levels are
+// recorded only if the component passed the validation.
+func (r *Report) VSAPredicate(c Component, policyURI string, verifiedLevels []string) (vsa.Predicate, error) {
+	if r.policy == nil {
+		return vsa.Predicate{}, errors.New("the report holds no policy to create the Verification Summary Attestation with")
+	}
+
+	ref, err := name.ParseReference(c.ContainerImage)
+	if err != nil {
+		return vsa.Predicate{}, err
+	}
+
+	digest, err := vsa.PolicyDigest(r.policy, c.Name, ref.Context().Name())
+	if err != nil {
+		return vsa.Predicate{}, err
+	}
+
+	predicate := vsa.Predicate{
+		Verifier: vsa.Verifier{
+			ID:      vsa.VerifierID,
+			Version: map[string]string{"ec": r.EcVersion},
+		},
+		TimeVerified: r.created,
+		ResourceURI:  c.ContainerImage,
+		Policy: vsa.ResourceDescriptor{
+			URI:    policyURI,
+			Digest: map[string]string{"sha256": digest},
+			Annotations: map[string]any{
+				"effectiveTime": r.EffectiveTime.Format(time.RFC3339),
+			},
+		},
+		VerificationResult: vsa.Passed,
+		VerifiedLevels:     verifiedLevels,
+	}
+
+	if !c.Success {
+		predicate.VerificationResult = vsa.Failed
+		predicate.VerifiedLevels = []string{vsa.Failed}
+	}
+
+	if predicate.VerifiedLevels == nil {
+		predicate.VerifiedLevels = []string{}
+	}
+
+	return predicate, nil
+}
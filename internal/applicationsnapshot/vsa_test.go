@@ -0,0 +1,110 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package applicationsnapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	app "github.com/redhat-appstudio/application-api/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+	"github.com/enterprise-contract/ec-cli/internal/vsa"
+)
+
+func Test_VSAPredicate(t *testing.T) {
+	created := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	p, err := policy.NewInputPolicy(context.Background(), `{"publicKey": "k8s://ns/key"}`, "2023-04-01")
+	require.NoError(t, err)
+	r := Report{
+		created:       created,
+		EcVersion:     "v1.2.3",
+		EffectiveTime: time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC),
+		Policy:        p.Spec(),
+		policy:        p,
+	}
+
+	// same digest as when looking for the attestation while validating
+	policyDigest, err := vsa.PolicyDigest(p, "spam", "quay.io/caf/spam")
+	require.NoError(t, err)
+
+	component := func(success bool) Component {
+		return Component{
+			SnapshotComponent: app.SnapshotComponent{
+				Name:           "spam",
+				ContainerImage: "quay.io/caf/spam@sha256:4e388ab32b10dc8dbc7e28144f552830adc74787c1e2c0824032078a79f227fb",
+			},
+			Success: success,
+		}
+	}
+
+	cases := []struct {
+		name           string
+		component      Component
+		verifiedLevels []string
+		result         string
+		expectedLevels []string
+	}{
+		{
+			name:           "passed",
+			component:      component(true),
+			verifiedLevels: []string{"SLSA_BUILD_LEVEL_3"},
+			result:         vsa.Passed,
+			expectedLevels: []string{"SLSA_BUILD_LEVEL_3"},
+		},
+		{
+			name:           "passed without levels",
+			component:      component(true),
+			result:         vsa.Passed,
+			expectedLevels: []string{},
+		},
+		{
+			name:           "failed",
+			component:      component(false),
+			verifiedLevels: []string{"SLSA_BUILD_LEVEL_3"},
+			result:         vsa.Failed,
+			expectedLevels: []string{vsa.Failed},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			predicate, err := r.VSAPredicate(c.component, "github.com/org/policy//policy.yaml", c.verifiedLevels)
+			require.NoError(t, err)
+
+			assert.Equal(t, vsa.Verifier{ID: vsa.VerifierID, Version: map[string]string{"ec": "v1.2.3"}}, predicate.Verifier)
+			assert.Equal(t, created, predicate.TimeVerified)
+			assert.Equal(t, "quay.io/caf/spam@sha256:4e388ab32b10dc8dbc7e28144f552830adc74787c1e2c0824032078a79f227fb", predicate.ResourceURI)
+			assert.Equal(t, "github.com/org/policy//policy.yaml", predicate.Policy.URI)
+			assert.Equal(t, policyDigest, predicate.Policy.Digest["sha256"])
+			assert.Equal(t, map[string]any{"effectiveTime": "2023-04-01T00:00:00Z"}, predicate.Policy.Annotations)
+			assert.Equal(t, c.result, predicate.VerificationResult)
+			assert.Equal(t, c.expectedLevels, predicate.VerifiedLevels)
+		})
+	}
+}
+
+func Test_VSAPredicateWithoutPolicy(t *testing.T) {
+	r := Report{}
+	_, err := r.VSAPredicate(Component{}, "", nil)
+	assert.ErrorContains(t, err, "the report holds no policy")
+}
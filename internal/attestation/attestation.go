@@ -17,6 +17,17 @@
 package attestation
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	v02 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
+	slsa1 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v1"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/types"
+
 	"github.com/enterprise-contract/ec-cli/internal/output"
 	e "github.com/enterprise-contract/ec-cli/pkg/error"
 )
@@ -28,11 +39,148 @@ var (
 	AT004 = e.NewError("AT004", "Unsupported attestation predicate type", e.ErrorExitStatus)
 )
 
+// StatementInTotoV1 is the in-toto Statement type of the v1 attestation
+// framework, used by producers of SLSA Provenance v1.0.
+const StatementInTotoV1 = "https://in-toto.io/Statement/v1"
+
 // Attestation holds the raw attestation data, usually fetched from the
 // signature envelope's payload; statement of a particular type and any
 // signing information.
 type Attestation[T any] interface {
 	Data() []byte
+	PredicateType() string
 	Statement() T
 	Signatures() []output.EntitySignature
 }
+
+// payloadParser parses the statement embeded in the DSSE envelope payload
+// into an Attestation of a particular predicate type.
+type payloadParser func(cosign.AttestationPayload, []byte) (Attestation[any], error)
+
+// predicateParsers holds parsers for predicate types that are parsed into
+// statements of a specific type, statements of any other predicate type are
+// parsed as generic in-toto Statements.
+var predicateParsers = map[string]payloadParser{
+	v02.PredicateSLSAProvenance:   untypedParser(slsaProvenanceFromPayload),
+	slsa1.PredicateSLSAProvenance: untypedParser(slsaProvenanceV1FromPayload),
+}
+
+// IsSLSAProvenance returns true if the predicate type is one of the supported
+// versions of SLSA Provenance.
+func IsSLSAProvenance(predicateType string) bool {
+	return predicateType == v02.PredicateSLSAProvenance || predicateType == slsa1.PredicateSLSAProvenance
+}
+
+// FromLayer parses the in-toto Statement of any predicate type from the
+// provided OCI layer. The predicate type of the statement determines the type
+// of the statement returned by the Attestation. Expects that the layer
+// contains DSSE JSON with the embeded in-toto Statement payload.
+func FromLayer(layer v1.Layer) (Attestation[any], error) {
+	payload, embeded, err := payloadFromLayer(layer)
+	if err != nil {
+		return nil, err
+	}
+
+	var header in_toto.StatementHeader
+	if err := json.Unmarshal(embeded, &header); err != nil {
+		return nil, AT002.CausedBy(err)
+	}
+
+	if parse, ok := predicateParsers[header.PredicateType]; ok {
+		return parse(payload, embeded)
+	}
+
+	return untypedParser(statementFromPayload)(payload, embeded)
+}
+
+// Untyped returns the Attestation with the statement of a specific type as
+// an Attestation of any statement type, so that attestations of different
+// predicate types can be handled together.
+func Untyped[T any](a Attestation[T]) Attestation[any] {
+	return untyped[T]{a}
+}
+
+type untyped[T any] struct {
+	Attestation[T]
+}
+
+func (u untyped[T]) Statement() any {
+	return u.Attestation.Statement()
+}
+
+func untypedParser[T any](parse func(cosign.AttestationPayload, []byte) (Attestation[T], error)) payloadParser {
+	return func(payload cosign.AttestationPayload, embeded []byte) (Attestation[any], error) {
+		a, err := parse(payload, embeded)
+		if err != nil {
+			return nil, err
+		}
+
+		return Untyped(a), nil
+	}
+}
+
+// isInTotoStatement returns true if the statement type is one of the
+// supported in-toto Statement types.
+func isInTotoStatement(typ string) bool {
+	return typ == in_toto.StatementInTotoV01 || typ == StatementInTotoV1
+}
+
+// signaturesOf returns the signatures from the DSSE envelope payload with
+// the provided statement description as metadata.
+func signaturesOf(payload cosign.AttestationPayload, metadata map[string]string) []output.EntitySignature {
+	var sigs []output.EntitySignature
+	for _, sig := range payload.Signatures {
+		sigs = append(sigs, output.EntitySignature{
+			KeyID:     sig.KeyID,
+			Signature: sig.Sig,
+			Metadata:  metadata,
+		})
+	}
+
+	return sigs
+}
+
+// payloadFromLayer reads the DSSE envelope from the provided OCI layer and
+// returns it along with the decoded bytes of the embeded in-toto statement.
+func payloadFromLayer(layer v1.Layer) (cosign.AttestationPayload, []byte, error) {
+	var payload cosign.AttestationPayload
+
+	if layer == nil {
+		return payload, nil, AT001
+	}
+	typ, err := layer.MediaType()
+	if err != nil {
+		return payload, nil, AT002.CausedBy(err)
+	}
+
+	if typ != types.DssePayloadType {
+		return payload, nil, AT002.CausedByF("Expecting media type of `%s`, received: `%s`", types.DssePayloadType, typ)
+	}
+
+	reader, err := layer.Uncompressed()
+	if err != nil {
+		return payload, nil, AT002.CausedBy(err)
+	}
+	defer reader.Close()
+
+	payloadBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return payload, nil, AT002.CausedBy(err)
+	}
+
+	err = json.Unmarshal(payloadBytes, &payload)
+	if err != nil {
+		return payload, nil, AT002.CausedBy(err)
+	}
+
+	if payload.PayLoad == "" {
+		return payload, nil, AT002.CausedByF("No `payload` data found")
+	}
+
+	embeded, err := base64.StdEncoding.DecodeString(payload.PayLoad)
+	if err != nil {
+		return payload, nil, AT002.CausedBy(err)
+	}
+
+	return payload, embeded, nil
+}
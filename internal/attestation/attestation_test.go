@@ -0,0 +1,147 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package attestation
+
+import (
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	v02 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
+	ct "github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/output"
+	e "github.com/enterprise-contract/ec-cli/pkg/error"
+)
+
+func TestFromLayer(t *testing.T) {
+	cases := []struct {
+		name          string
+		statement     string
+		predicateType string
+		expected      any
+		err           e.Error
+	}{
+		{
+			name:      "nil layer",
+			statement: "",
+			err:       AT001,
+		},
+		{
+			name:          "SLSA Provenance v0.2",
+			statement:     `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://slsa.dev/provenance/v0.2","predicate":{"buildType":"https://my.build.type"}}`,
+			predicateType: "https://slsa.dev/provenance/v0.2",
+			expected: in_toto.ProvenanceStatementSLSA02{
+				StatementHeader: in_toto.StatementHeader{
+					Type:          in_toto.StatementInTotoV01,
+					PredicateType: v02.PredicateSLSAProvenance,
+				},
+				Predicate: v02.ProvenancePredicate{
+					BuildType: "https://my.build.type",
+				},
+			},
+		},
+		{
+			name:          "SLSA Provenance v1.0",
+			statement:     `{"_type":"https://in-toto.io/Statement/v1","predicateType":"https://slsa.dev/provenance/v1","predicate":{}}`,
+			predicateType: "https://slsa.dev/provenance/v1",
+			expected: in_toto.ProvenanceStatementSLSA1{
+				StatementHeader: in_toto.StatementHeader{
+					Type:          StatementInTotoV1,
+					PredicateType: "https://slsa.dev/provenance/v1",
+				},
+			},
+		},
+		{
+			name:          "SPDX SBOM",
+			statement:     `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://spdx.dev/Document","predicate":{"spdxVersion":"SPDX-2.3"}}`,
+			predicateType: "https://spdx.dev/Document",
+			expected: in_toto.Statement{
+				StatementHeader: in_toto.StatementHeader{
+					Type:          in_toto.StatementInTotoV01,
+					PredicateType: "https://spdx.dev/Document",
+				},
+				Predicate: map[string]any{
+					"spdxVersion": "SPDX-2.3",
+				},
+			},
+		},
+		{
+			name:      "unsupported statement type",
+			statement: `{"_type":"kaboom","predicateType":"https://spdx.dev/Document"}`,
+			err:       AT003.CausedByF("kaboom"),
+		},
+		{
+			name:      "no predicate type",
+			statement: `{"_type":"https://in-toto.io/Statement/v0.1"}`,
+			err:       AT004.CausedByF(""),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var att Attestation[any]
+			var err error
+			if c.statement == "" {
+				att, err = FromLayer(nil)
+			} else {
+				layer := mockLayer{&mock.Mock{}}
+				layer.On("MediaType").Return(types.MediaType(ct.DssePayloadType), nil)
+				layer.On("Uncompressed").Return(buffy(`{"payload":"`+encode(c.statement)+`"}`), nil)
+				att, err = FromLayer(layer)
+			}
+
+			if c.err != nil {
+				assert.True(t, c.err.Alike(err), "Expecting `%v` to be alike: `%v`", err, c.err)
+				assert.Nil(t, att)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, c.predicateType, att.PredicateType())
+			assert.Equal(t, c.expected, att.Statement())
+			assert.JSONEq(t, c.statement, string(att.Data()))
+		})
+	}
+}
+
+func TestFromLayerSignatures(t *testing.T) {
+	layer := mockLayer{&mock.Mock{}}
+	statement := encode(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://cosign.sigstore.dev/attestation/vuln/v1","predicate":{}}`)
+	layer.On("MediaType").Return(types.MediaType(ct.DssePayloadType), nil)
+	layer.On("Uncompressed").Return(buffy(`{"signatures": [{"keyid": "key-id-1", "sig": "sig-1"}], "payload":"`+statement+`"}`), nil)
+
+	att, err := FromLayer(layer)
+	require.NoError(t, err)
+
+	assert.Equal(t, []output.EntitySignature{
+		{KeyID: "key-id-1", Signature: "sig-1", Metadata: map[string]string{
+			"type":          in_toto.StatementInTotoV01,
+			"predicateType": "https://cosign.sigstore.dev/attestation/vuln/v1",
+		}},
+	}, att.Signatures())
+}
+
+func TestIsSLSAProvenance(t *testing.T) {
+	assert.True(t, IsSLSAProvenance(v02.PredicateSLSAProvenance))
+	assert.True(t, IsSLSAProvenance("https://slsa.dev/provenance/v1"))
+	assert.False(t, IsSLSAProvenance("https://spdx.dev/Document"))
+	assert.False(t, IsSLSAProvenance(""))
+}
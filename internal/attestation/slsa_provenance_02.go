@@ -17,15 +17,12 @@
 package attestation
 
 import (
-	"encoding/base64"
 	"encoding/json"
-	"io"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/in-toto/in-toto-golang/in_toto"
 	v02 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
-	"github.com/sigstore/cosign/v2/pkg/types"
 
 	"github.com/enterprise-contract/ec-cli/internal/output"
 )
@@ -34,44 +31,15 @@ import (
 // layer. Expects that the layer contains DSSE JSON with the embeded SLSA
 // Provenance v0.2 payload.
 func SLSAProvenanceFromLayer(layer v1.Layer) (Attestation[in_toto.ProvenanceStatementSLSA02], error) {
-	if layer == nil {
-		return nil, AT001
-	}
-	typ, err := layer.MediaType()
-	if err != nil {
-		return nil, AT002.CausedBy(err)
-	}
-
-	if typ != types.DssePayloadType {
-		return nil, AT002.CausedByF("Expecting media type of `%s`, received: `%s`", types.DssePayloadType, typ)
-	}
-
-	reader, err := layer.Uncompressed()
-	if err != nil {
-		return nil, AT002.CausedBy(err)
-	}
-	defer reader.Close()
-
-	payloadBytes, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, AT002.CausedBy(err)
-	}
-
-	var payload cosign.AttestationPayload
-	err = json.Unmarshal(payloadBytes, &payload)
+	payload, embeded, err := payloadFromLayer(layer)
 	if err != nil {
-		return nil, AT002.CausedBy(err)
+		return nil, err
 	}
 
-	if payload.PayLoad == "" {
-		return nil, AT002.CausedByF("No `payload` data found")
-	}
-
-	embeded, err := base64.StdEncoding.DecodeString(payload.PayLoad)
-	if err != nil {
-		return nil, AT002.CausedBy(err)
-	}
+	return slsaProvenanceFromPayload(payload, embeded)
+}
 
+func slsaProvenanceFromPayload(payload cosign.AttestationPayload, embeded []byte) (Attestation[in_toto.ProvenanceStatementSLSA02], error) {
 	var statement in_toto.ProvenanceStatementSLSA02
 	if err := json.Unmarshal(embeded, &statement); err != nil {
 		return nil, AT002.CausedBy(err)
@@ -98,6 +66,10 @@ func (a slsaProvenance) Data() []byte {
 	return a.bytes
 }
 
+func (a slsaProvenance) PredicateType() string {
+	return a.statement.PredicateType
+}
+
 func (a slsaProvenance) Statement() in_toto.ProvenanceStatementSLSA02 {
 	return a.statement
 }
@@ -105,16 +77,7 @@ func (a slsaProvenance) Statement() in_toto.ProvenanceStatementSLSA02 {
 func (a slsaProvenance) Signatures() []output.EntitySignature {
 	metadata := describeStatement(a.statement)
 
-	var sigs []output.EntitySignature
-	for _, sig := range a.payload.Signatures {
-		sigs = append(sigs, output.EntitySignature{
-			KeyID:     sig.KeyID,
-			Signature: sig.Sig,
-			Metadata:  metadata,
-		})
-	}
-
-	return sigs
+	return signaturesOf(a.payload, metadata)
 }
 
 func describeStatement(statement in_toto.ProvenanceStatementSLSA02) map[string]string {
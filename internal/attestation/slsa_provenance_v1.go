@@ -0,0 +1,95 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package attestation
+
+import (
+	"encoding/json"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	slsa1 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v1"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+
+	"github.com/enterprise-contract/ec-cli/internal/output"
+)
+
+// SLSAProvenanceV1FromLayer parses the SLSA Provenance v1.0 from the provided
+// OCI layer. Expects that the layer contains DSSE JSON with the embeded SLSA
+// Provenance v1.0 payload. Both the in-toto Statement v0.1 and v1 types are
+// accepted as the SLSA v1.0 specification allows either.
+func SLSAProvenanceV1FromLayer(layer v1.Layer) (Attestation[in_toto.ProvenanceStatementSLSA1], error) {
+	payload, embeded, err := payloadFromLayer(layer)
+	if err != nil {
+		return nil, err
+	}
+
+	return slsaProvenanceV1FromPayload(payload, embeded)
+}
+
+func slsaProvenanceV1FromPayload(payload cosign.AttestationPayload, embeded []byte) (Attestation[in_toto.ProvenanceStatementSLSA1], error) {
+	var statement in_toto.ProvenanceStatementSLSA1
+	if err := json.Unmarshal(embeded, &statement); err != nil {
+		return nil, AT002.CausedBy(err)
+	}
+
+	if !isInTotoStatement(statement.Type) {
+		return nil, AT003.CausedByF(statement.Type)
+	}
+
+	if statement.PredicateType != slsa1.PredicateSLSAProvenance {
+		return nil, AT004.CausedByF(statement.PredicateType)
+	}
+
+	return slsaProvenanceV1{statement: statement, payload: payload, bytes: embeded}, nil
+}
+
+type slsaProvenanceV1 struct {
+	statement in_toto.ProvenanceStatementSLSA1
+	payload   cosign.AttestationPayload
+	bytes     []byte
+}
+
+func (a slsaProvenanceV1) Data() []byte {
+	return a.bytes
+}
+
+func (a slsaProvenanceV1) PredicateType() string {
+	return a.statement.PredicateType
+}
+
+func (a slsaProvenanceV1) Statement() in_toto.ProvenanceStatementSLSA1 {
+	return a.statement
+}
+
+func (a slsaProvenanceV1) Signatures() []output.EntitySignature {
+	metadata := describeStatementV1(a.statement)
+
+	return signaturesOf(a.payload, metadata)
+}
+
+func describeStatementV1(statement in_toto.ProvenanceStatementSLSA1) map[string]string {
+	description := map[string]string{
+		"predicateType": statement.PredicateType,
+		"type":          statement.Type,
+	}
+
+	if statement.Predicate.BuildDefinition.BuildType != "" {
+		description["predicateBuildType"] = statement.Predicate.BuildDefinition.BuildType
+	}
+
+	return description
+}
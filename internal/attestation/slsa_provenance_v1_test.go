@@ -0,0 +1,163 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package attestation
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	slsa1 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v1"
+	ct "github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/output"
+	e "github.com/enterprise-contract/ec-cli/pkg/error"
+)
+
+func TestSLSAProvenanceV1FromLayerNilLayer(t *testing.T) {
+	sp, err := SLSAProvenanceV1FromLayer(nil)
+	assert.True(t, AT001.Alike(err), "Expecting `%v` to be alike: `%v`", err, AT001)
+	assert.Nil(t, sp)
+}
+
+func TestSLSAProvenanceV1FromLayer(t *testing.T) {
+	cases := []struct {
+		name      string
+		setup     func(l *mockLayer)
+		data      string
+		statement in_toto.ProvenanceStatementSLSA1
+		err       e.Error
+	}{
+		{
+			name: "unsupported media type",
+			setup: func(l *mockLayer) {
+				l.On("MediaType").Return(types.MediaType("xxx"), nil)
+			},
+			err: AT002.CausedByF("Expecting media type of `application/vnd.dsse.envelope.v1+json`, received: `xxx`"),
+		},
+		{
+			name: "empty payload JSON",
+			setup: func(l *mockLayer) {
+				l.On("MediaType").Return(types.MediaType(ct.DssePayloadType), nil)
+				l.On("Uncompressed").Return(buffy(`{"payload":"`+base64.StdEncoding.EncodeToString([]byte("{}"))+`"}`), nil)
+			},
+			err: AT003.CausedByF(""),
+		},
+		{
+			name: "SLSA Provenance v0.2 predicate type",
+			setup: func(l *mockLayer) {
+				provenancePayload := encode(`{
+						"_type": "https://in-toto.io/Statement/v0.1",
+						"predicateType":"https://slsa.dev/provenance/v0.2"
+					}`)
+				l.On("MediaType").Return(types.MediaType(ct.DssePayloadType), nil)
+				l.On("Uncompressed").Return(buffy(`{"payload": "`+provenancePayload+`"}`), nil)
+			},
+			err: AT004.CausedByF("https://slsa.dev/provenance/v0.2"),
+		},
+		{
+			name: "valid with Statement v0.1",
+			data: `{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://slsa.dev/provenance/v1","predicate":{"buildDefinition":{"buildType":"https://my.build.type"}}}`,
+			setup: func(l *mockLayer) {
+				l.On("MediaType").Return(types.MediaType(ct.DssePayloadType), nil)
+				l.On("Uncompressed").Return(buffy(`{"payload":"`+base64.StdEncoding.EncodeToString([]byte(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://slsa.dev/provenance/v1","predicate":{"buildDefinition":{"buildType":"https://my.build.type"}}}`))+`"}`), nil)
+			},
+			statement: in_toto.ProvenanceStatementSLSA1{
+				StatementHeader: in_toto.StatementHeader{
+					Type:          in_toto.StatementInTotoV01,
+					PredicateType: slsa1.PredicateSLSAProvenance,
+				},
+				Predicate: slsa1.ProvenancePredicate{
+					BuildDefinition: slsa1.ProvenanceBuildDefinition{
+						BuildType: "https://my.build.type",
+					},
+				},
+			},
+		},
+		{
+			name: "valid with Statement v1",
+			data: `{"_type":"https://in-toto.io/Statement/v1","predicateType":"https://slsa.dev/provenance/v1","predicate":{"buildDefinition":{"buildType":"https://my.build.type"}}}`,
+			setup: func(l *mockLayer) {
+				l.On("MediaType").Return(types.MediaType(ct.DssePayloadType), nil)
+				l.On("Uncompressed").Return(buffy(`{"payload":"`+base64.StdEncoding.EncodeToString([]byte(`{"_type":"https://in-toto.io/Statement/v1","predicateType":"https://slsa.dev/provenance/v1","predicate":{"buildDefinition":{"buildType":"https://my.build.type"}}}`))+`"}`), nil)
+			},
+			statement: in_toto.ProvenanceStatementSLSA1{
+				StatementHeader: in_toto.StatementHeader{
+					Type:          StatementInTotoV1,
+					PredicateType: slsa1.PredicateSLSAProvenance,
+				},
+				Predicate: slsa1.ProvenancePredicate{
+					BuildDefinition: slsa1.ProvenanceBuildDefinition{
+						BuildType: "https://my.build.type",
+					},
+				},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			layer := mockLayer{&mock.Mock{}}
+
+			if c.setup != nil {
+				c.setup(&layer)
+			}
+
+			sp, err := SLSAProvenanceV1FromLayer(layer)
+			if c.err == nil {
+				require.Nil(t, err)
+				require.NotNil(t, sp)
+			} else {
+				require.Nil(t, sp)
+				assert.True(t, c.err.Alike(err), "Expecting `%v` to be alike: `%v`", err, c.err)
+				return
+			}
+
+			assert.JSONEq(t, c.data, string(sp.Data()))
+			assert.Equal(t, c.statement, sp.Statement())
+		})
+	}
+}
+
+func TestSLSAProvenanceV1EntitySignature(t *testing.T) {
+	layer := mockLayer{&mock.Mock{}}
+	sig1 := `{"keyid": "key-id-1", "sig": "sig-1"}`
+	provenancePayload := encode(`{
+			"_type": "https://in-toto.io/Statement/v1",
+			"predicateType":"https://slsa.dev/provenance/v1",
+			"predicate":{"buildDefinition":{"buildType":"https://my.build.type"}}
+		}`)
+	payload := fmt.Sprintf(`{"signatures": [%s], "payload": "`+provenancePayload+`"}`, sig1)
+	layer.On("MediaType").Return(types.MediaType(ct.DssePayloadType), nil)
+	layer.On("Uncompressed").Return(buffy(payload), nil)
+
+	sp, err := SLSAProvenanceV1FromLayer(layer)
+	require.NoError(t, err)
+
+	assert.Equal(t, []output.EntitySignature{
+		{KeyID: "key-id-1", Signature: "sig-1", Metadata: map[string]string{
+			"type":               "https://in-toto.io/Statement/v1",
+			"predicateBuildType": "https://my.build.type",
+			"predicateType":      "https://slsa.dev/provenance/v1",
+		}},
+	}, sp.Signatures())
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package attestation
+
+import (
+	"encoding/json"
+
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+
+	"github.com/enterprise-contract/ec-cli/internal/output"
+)
+
+// statementFromPayload parses the in-toto Statement of any predicate type
+// from the DSSE envelope payload. The predicate is kept as is, i.e. it is not
+// parsed into a specific type.
+func statementFromPayload(payload cosign.AttestationPayload, embeded []byte) (Attestation[in_toto.Statement], error) {
+	var statement in_toto.Statement
+	if err := json.Unmarshal(embeded, &statement); err != nil {
+		return nil, AT002.CausedBy(err)
+	}
+
+	if !isInTotoStatement(statement.Type) {
+		return nil, AT003.CausedByF(statement.Type)
+	}
+
+	if statement.PredicateType == "" {
+		return nil, AT004.CausedByF(statement.PredicateType)
+	}
+
+	return genericStatement{statement: statement, payload: payload, bytes: embeded}, nil
+}
+
+type genericStatement struct {
+	statement in_toto.Statement
+	payload   cosign.AttestationPayload
+	bytes     []byte
+}
+
+func (a genericStatement) Data() []byte {
+	return a.bytes
+}
+
+func (a genericStatement) PredicateType() string {
+	return a.statement.PredicateType
+}
+
+func (a genericStatement) Statement() in_toto.Statement {
+	return a.statement
+}
+
+func (a genericStatement) Signatures() []output.EntitySignature {
+	metadata := map[string]string{
+		"predicateType": a.statement.PredicateType,
+		"type":          a.statement.Type,
+	}
+
+	return signaturesOf(a.payload, metadata)
+}
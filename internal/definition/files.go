@@ -0,0 +1,242 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package definition
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+type contextKey string
+
+const excludeKey contextKey = "ec.definition.exclude"
+
+// WithExclude returns a context holding the patterns of the files to leave out
+// when looking up the definition files in a directory or matching a glob
+// pattern.
+func WithExclude(ctx context.Context, patterns []string) context.Context {
+	return context.WithValue(ctx, excludeKey, patterns)
+}
+
+func excludePatterns(ctx context.Context) []string {
+	patterns, _ := ctx.Value(excludeKey).([]string)
+	return patterns
+}
+
+// isGlob returns true if the path contains any of the glob meta characters
+func isGlob(fpath string) bool {
+	return strings.ContainsAny(fpath, "*?[")
+}
+
+// match returns true if the pattern matches the whole name, in addition to
+// the path.Match syntax a "**" segment matches any number of directories
+func match(pattern, name string) bool {
+	return matchSegments(strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/"),
+		strings.Split(filepath.ToSlash(filepath.Clean(name)), "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(name) == 0 {
+		return false
+	}
+
+	if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+		return false
+	}
+
+	return matchSegments(pattern[1:], name[1:])
+}
+
+// excluded returns true if any of the patterns matches the path of the file,
+// patterns without a directory separator are also matched against the name of
+// the file.
+func excluded(patterns []string, fpath string) bool {
+	for _, p := range patterns {
+		if match(p, fpath) {
+			return true
+		}
+		if !strings.Contains(p, "/") && match(p, filepath.Base(fpath)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// globLookup returns the JSON and YAML files matching the glob pattern
+func globLookup(ctx context.Context, pattern string) ([]string, error) {
+	fs := utils.FS(ctx)
+	exclude := excludePatterns(ctx)
+
+	// Walk from the longest leading part of the pattern without meta
+	// characters
+	segments := strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/")
+	root := ""
+	for _, s := range segments {
+		if isGlob(s) {
+			break
+		}
+		root = path.Join(root, s)
+	}
+	if strings.HasPrefix(filepath.ToSlash(pattern), "/") {
+		root = "/" + root
+	}
+	if root == "" {
+		root = "."
+	}
+
+	files, err := utils.WalkJsonOrYamlFiles(fs, filepath.FromSlash(root), func(fpath string) bool {
+		return match(pattern, fpath) && !excluded(exclude, fpath)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files match the pattern %v", pattern)
+	}
+
+	return files, nil
+}
+
+// document is a single document of a definition file, name identifies it in
+// the report
+type document struct {
+	path string
+	name string
+}
+
+// splitDocuments returns the documents within the files, YAML files holding
+// multiple documents have each document written to a separate file named
+// after the original file and the position of the document, e.g.
+// pipeline.yaml#2 for the second document. The files written are removed if
+// an error is returned.
+func splitDocuments(ctx context.Context, files []string) (_ []document, err error) {
+	fs := utils.FS(ctx)
+
+	var created []string
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, c := range created {
+			_ = fs.Remove(c)
+		}
+	}()
+
+	var documents []document
+	for _, f := range files {
+		ext := filepath.Ext(f)
+		if ext != ".yaml" && ext != ".yml" {
+			documents = append(documents, document{path: f, name: f})
+			continue
+		}
+
+		content, err := afero.ReadFile(fs, f)
+		if err != nil {
+			return nil, err
+		}
+
+		parts, err := yamlDocuments(content)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the YAML documents of %s: %w", f, err)
+		}
+
+		if len(parts) <= 1 {
+			documents = append(documents, document{path: f, name: f})
+			continue
+		}
+
+		for i, part := range parts {
+			file, err := afero.TempFile(fs, "", "definition-document-*"+ext)
+			if err != nil {
+				return nil, err
+			}
+			created = append(created, file.Name())
+
+			_, err = file.Write(part)
+			file.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			documents = append(documents, document{path: file.Name(), name: fmt.Sprintf("%s#%d", f, i+1)})
+		}
+	}
+
+	return documents, nil
+}
+
+// yamlDocuments returns the non-empty documents of the YAML stream
+func yamlDocuments(content []byte) ([][]byte, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+
+	var documents [][]byte
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !emptyDocument(doc) {
+			documents = append(documents, doc)
+		}
+	}
+
+	return documents, nil
+}
+
+// emptyDocument returns true if the document holds only separators, comments
+// and whitespace
+func emptyDocument(doc []byte) bool {
+	for _, line := range strings.Split(string(doc), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && line != "---" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package definition
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		name    string
+		match   bool
+	}{
+		{pattern: "*.yaml", name: "a.yaml", match: true},
+		{pattern: "*.yaml", name: "dir/a.yaml", match: false},
+		{pattern: "dir/*.yaml", name: "dir/a.yaml", match: true},
+		{pattern: "dir/**/*.yaml", name: "dir/a.yaml", match: true},
+		{pattern: "dir/**/*.yaml", name: "dir/sub/deeper/a.yaml", match: true},
+		{pattern: "dir/**/*.yaml", name: "other/a.yaml", match: false},
+		{pattern: "**/test/**", name: "dir/test/a.json", match: true},
+		{pattern: "**/test/**", name: "dir/tests/a.json", match: false},
+		{pattern: "/dir/**", name: "/dir/sub/a.json", match: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.pattern+" "+c.name, func(t *testing.T) {
+			assert.Equal(t, c.match, match(c.pattern, c.name))
+		})
+	}
+}
+
+func TestExcluded(t *testing.T) {
+	patterns := []string{"*.json", "**/test/**"}
+
+	assert.True(t, excluded(patterns, "dir/a.json"))
+	assert.True(t, excluded(patterns, "dir/test/a.yaml"))
+	assert.False(t, excluded(patterns, "dir/a.yaml"))
+	assert.False(t, excluded(nil, "dir/a.json"))
+}
+
+func definitionFS(t *testing.T) afero.Fs {
+	fs := afero.NewMemMapFs()
+	for _, f := range []string{
+		"/defs/a.yaml",
+		"/defs/b.json",
+		"/defs/README.md",
+		"/defs/sub/c.yml",
+		"/defs/sub/test/d.yaml",
+		"/defs/.git/e.yaml",
+	} {
+		require.NoError(t, afero.WriteFile(fs, f, []byte("kind: Task"), 0644))
+	}
+
+	return fs
+}
+
+func TestFileLookupRecursive(t *testing.T) {
+	ctx := utils.WithFS(context.Background(), definitionFS(t))
+
+	files, err := fileLookup(ctx, "/defs")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/defs/a.yaml", "/defs/b.json", "/defs/sub/c.yml", "/defs/sub/test/d.yaml"}, files)
+
+	files, err = fileLookup(WithExclude(ctx, []string{"**/test/**", "*.json"}), "/defs")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/defs/a.yaml", "/defs/sub/c.yml"}, files)
+}
+
+func TestGlobLookup(t *testing.T) {
+	ctx := utils.WithFS(context.Background(), definitionFS(t))
+
+	files, err := globLookup(ctx, "/defs/*.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/defs/a.yaml"}, files)
+
+	files, err = globLookup(ctx, "/defs/**/*.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/defs/a.yaml", "/defs/sub/test/d.yaml"}, files)
+
+	files, err = globLookup(WithExclude(ctx, []string{"**/test/**"}), "/defs/**/*.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/defs/a.yaml"}, files)
+
+	_, err = globLookup(ctx, "/defs/**/*.xml")
+	assert.EqualError(t, err, "no files match the pattern /defs/**/*.xml")
+}
+
+func TestSplitDocuments(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/single.yaml", []byte("---\nkind: Task\n"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/multi.yaml", []byte("kind: Task\n---\n# comment\n---\nkind: Pipeline\n"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/file.json", []byte(`{"kind": "Task"}`), 0644))
+	ctx := utils.WithFS(context.Background(), fs)
+
+	documents, err := splitDocuments(ctx, []string{"/single.yaml", "/multi.yaml", "/file.json"})
+	require.NoError(t, err)
+	require.Len(t, documents, 4)
+
+	assert.Equal(t, document{path: "/single.yaml", name: "/single.yaml"}, documents[0])
+	assert.Equal(t, "/multi.yaml#1", documents[1].name)
+	assert.Equal(t, "/multi.yaml#2", documents[2].name)
+	assert.Equal(t, document{path: "/file.json", name: "/file.json"}, documents[3])
+
+	first, err := afero.ReadFile(fs, documents[1].path)
+	require.NoError(t, err)
+	assert.Contains(t, string(first), "kind: Task")
+
+	second, err := afero.ReadFile(fs, documents[2].path)
+	require.NoError(t, err)
+	assert.Contains(t, string(second), "kind: Pipeline")
+}
+
+func TestSplitDocumentsCleanupOnError(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/multi.yaml", []byte("kind: Task\n---\nkind: Pipeline\n"), 0644))
+	ctx := utils.WithFS(context.Background(), fs)
+
+	_, err := splitDocuments(ctx, []string{"/multi.yaml", "/missing.yaml"})
+	require.Error(t, err)
+
+	leftover, err := afero.Glob(fs, filepath.Join(os.TempDir(), "definition-document-*"))
+	require.NoError(t, err)
+	assert.Empty(t, leftover)
+}
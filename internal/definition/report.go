@@ -17,74 +17,192 @@
 package definition
 
 import (
+	"bytes"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"time"
 
-	cOutput "github.com/open-policy-agent/conftest/output"
+	"github.com/jstemmer/go-junit-report/v2/junit"
 	"sigs.k8s.io/yaml"
 
 	"github.com/enterprise-contract/ec-cli/internal/format"
 	"github.com/enterprise-contract/ec-cli/internal/output"
+	"github.com/enterprise-contract/ec-cli/internal/sarif"
 	"github.com/enterprise-contract/ec-cli/internal/version"
 )
 
 type ReportItem struct {
-	Filename   string           `json:"filename"`
-	Violations []cOutput.Result `json:"violations"`
-	Warnings   []cOutput.Result `json:"warnings"`
-	Success    bool             `json:"success"`
+	Filename string `json:"filename"`
+	format.FileResults
 }
 
 type ReportFormat string
 
 const (
-	JSONReport string = "json"
-	YAMLReport string = "yaml"
+	JSONReport  string = "json"
+	YAMLReport  string = "yaml"
+	SARIFReport string = "sarif"
+	TextReport  string = "text"
+	// SummaryReport condenses the results of each definition file by rule
+	SummaryReport string = "summary"
+	JUnitReport   string = "junit"
 )
 
 type Report struct {
-	Definitions []ReportItem `json:"definitions"`
-	Success     bool         `json:"success"`
-	EcVersion   string       `json:"ec-version"`
+	Definitions   []ReportItem `json:"definitions"`
+	Success       bool         `json:"success"`
+	EcVersion     string       `json:"ec-version"`
+	created       time.Time
+	showSuccesses bool
+	// rules holds the metadata of the rules of the reported results
+	rules map[string]map[string]interface{}
 }
 
-func NewReport() Report {
+type summary struct {
+	Definitions []definitionSummary `json:"definitions"`
+	Success     bool                `json:"success"`
+}
+
+type definitionSummary struct {
+	Filename              string              `json:"filename"`
+	Success               bool                `json:"success"`
+	Violations            map[string][]string `json:"violations"`
+	Warnings              map[string][]string `json:"warnings"`
+	Successes             map[string][]string `json:"successes"`
+	FutureViolations      map[string][]string `json:"future_violations,omitempty"`
+	Exempted              map[string][]string `json:"exempted,omitempty"`
+	TotalViolations       int                 `json:"total_violations"`
+	TotalWarnings         int                 `json:"total_warnings"`
+	TotalSuccesses        int                 `json:"total_successes"`
+	TotalFutureViolations int                 `json:"total_future_violations,omitempty"`
+	TotalExempted         int                 `json:"total_exempted,omitempty"`
+}
+
+// NewReport returns an empty report of the validation of definition files,
+// successes are included if showSuccesses is true
+func NewReport(showSuccesses bool) Report {
 	info, _ := version.ComputeInfo()
 	return Report{
-		Success:   true,
-		EcVersion: info.Version,
+		Success:       true,
+		EcVersion:     info.Version,
+		created:       time.Now().UTC(),
+		showSuccesses: showSuccesses,
 	}
 }
 
+// Add records the results of the output grouped by the definition file they
+// pertain to, the definitions are kept sorted by their file name. If multiple
+// files are passed to the testRunner, conftest evaluates all files against
+// each namespace.
 func (r *Report) Add(o output.Output) {
-	// group the results by filename. If multiple files are passed
-	// to the testRunner, conftest evaluates all files against each namespace.
-	itemsByFile := make(map[string]ReportItem)
-
-	// check contains results from each namespace evaluated per definition
-	for _, check := range o.PolicyCheck {
-		if _, ok := itemsByFile[check.FileName]; !ok {
-			itemsByFile[check.FileName] = ReportItem{
-				Violations: []cOutput.Result{},
-				Warnings:   []cOutput.Result{},
-			}
+	if r.rules == nil {
+		r.rules = map[string]map[string]interface{}{}
+	}
+	for code, metadata := range o.Rules {
+		r.rules[code] = metadata
+	}
+
+	r.Definitions, r.Success = format.AddResultsByFile(r.Definitions, o.PolicyCheck, r.showSuccesses, func(d *ReportItem) (*string, *format.FileResults) {
+		return &d.Filename, &d.FileResults
+	})
+}
+
+// toSummary returns a condensed version of the report, the results of each
+// definition file are grouped by rule
+func (r *Report) toSummary() summary {
+	s := summary{Success: r.Success, Definitions: []definitionSummary{}}
+	for _, d := range r.Definitions {
+		ds := definitionSummary{
+			Filename:        d.Filename,
+			Success:         d.Success,
+			Violations:      format.CondensedMessages(d.Violations),
+			Warnings:        format.CondensedMessages(d.Warnings),
+			Successes:       format.CondensedMessages(d.Successes),
+			TotalViolations: len(d.Violations),
+			TotalWarnings:   len(d.Warnings),
+			TotalSuccesses:  len(d.Successes),
 		}
-		item := itemsByFile[check.FileName]
-		item.Violations = append(item.Violations, check.Failures...)
-		item.Warnings = append(item.Warnings, check.Warnings...)
-		item.Filename = check.FileName
-		itemsByFile[check.FileName] = item
+		if len(d.FutureViolations) > 0 {
+			ds.TotalFutureViolations = len(d.FutureViolations)
+			ds.FutureViolations = format.CondensedMessages(format.WithEnforcementDate(d.FutureViolations))
+		}
+		if len(d.Exempted) > 0 {
+			ds.TotalExempted = len(d.Exempted)
+			ds.Exempted = format.CondensedMessages(format.WithExceptionExpiry(d.Exempted))
+		}
+		s.Definitions = append(s.Definitions, ds)
 	}
 
-	for _, value := range itemsByFile {
-		value.Success = true
-		if len(value.Violations) > 0 {
-			value.Success = false
-			// set Report.Success to false if any violations
-			r.Success = false
+	return s
+}
+
+// toJUnit returns a version of the report in JUnit XML format, with a test
+// suite per definition file
+func (r *Report) toJUnit() junit.Testsuites {
+	report := junit.Testsuites{}
+	for _, d := range r.Definitions {
+		suite := junit.Testsuite{
+			Timestamp: r.created.Format(time.RFC3339),
+			Name:      d.Filename,
+			Properties: &[]junit.Property{
+				{Name: "success", Value: fmt.Sprint(d.Success)},
+			},
 		}
-		r.Definitions = append(r.Definitions, value)
+
+		format.AddJUnitTestcases(&suite, d.Successes, d.Violations, d.Warnings, d.FutureViolations, d.Exempted)
+
+		report.AddSuite(suite)
 	}
+
+	return report
+}
+
+// toSARIF returns a version of the report in the SARIF format, with the
+// definition files as the artifacts
+func (r *Report) toSARIF() sarif.Log {
+	b := sarif.NewBuilder(r.EcVersion)
+	b.AddRuleMetadata(r.rules)
+	for _, d := range r.Definitions {
+		b.Add(d.Filename, "", sarif.LevelError, d.Violations)
+		b.Add(d.Filename, "", sarif.LevelWarning, d.Warnings)
+		b.Add(d.Filename, "", sarif.LevelNote, d.FutureViolations)
+		b.Add(d.Filename, "", sarif.LevelNote, d.Exempted)
+	}
+
+	return b.Log()
+}
+
+// toText returns a human readable version of the report, grouped by
+// definition file, colored if color is true
+func (r *Report) toText(color bool) []byte {
+	p := format.NewPainter(color)
+	buf := bytes.Buffer{}
+
+	var violations, warnings int
+	for _, d := range r.Definitions {
+		violations += len(d.Violations)
+		warnings += len(d.Warnings)
+	}
+
+	fmt.Fprintf(&buf, "%s %s\n", p.Bold("Success:"), p.Status(r.Success, warnings))
+	fmt.Fprintf(&buf, "Definitions: %d, Violations: %s, Warnings: %s\n",
+		len(r.Definitions), p.Red(fmt.Sprint(violations)), p.Yellow(fmt.Sprint(warnings)))
+
+	for _, d := range r.Definitions {
+		buf.WriteString("\n")
+		fmt.Fprintf(&buf, "%s %s\n", p.Bold("Definition:"), d.Filename)
+		fmt.Fprintf(&buf, "Result: %s (violations: %d, warnings: %d)\n",
+			p.Status(d.Success, len(d.Warnings)), len(d.Violations), len(d.Warnings))
+
+		format.WriteTextResults(&buf, "Violation", p.Red, d.Violations)
+		format.WriteTextResults(&buf, "Warning", p.Yellow, d.Warnings)
+		format.WriteTextResults(&buf, "Future violation", p.Yellow, d.FutureViolations)
+		format.WriteTextResults(&buf, "Exempted", p.Faint, d.Exempted)
+		format.WriteTextResults(&buf, "Success", p.Green, d.Successes)
+	}
+
+	return buf.Bytes()
 }
 
 func (r *Report) Write(targetName string, p format.TargetParser) error {
@@ -106,6 +224,21 @@ func (r *Report) Write(targetName string, p format.TargetParser) error {
 		if data, err = yaml.Marshal(r); err != nil {
 			return err
 		}
+	case SARIFReport:
+		if data, err = json.Marshal(r.toSARIF()); err != nil {
+			return err
+		}
+	case SummaryReport:
+		if data, err = json.Marshal(r.toSummary()); err != nil {
+			return err
+		}
+	case JUnitReport:
+		if data, err = xml.Marshal(r.toJUnit()); err != nil {
+			return err
+		}
+	case TextReport:
+		// colored only when written to a terminal
+		data = r.toText(target.IsTerminal())
 	default:
 		return fmt.Errorf("unexpected report format: %s", target.Format)
 	}
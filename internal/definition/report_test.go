@@ -113,7 +113,7 @@ func TestReport(t *testing.T) {
 
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
-			r := NewReport()
+			r := NewReport(false)
 			for _, o := range c.output {
 				r.Add(o)
 			}
@@ -141,3 +141,161 @@ func TestReport(t *testing.T) {
 		})
 	}
 }
+
+func TestReportSARIF(t *testing.T) {
+	r := NewReport(false)
+	r.Add(output.Output{
+		PolicyCheck: evaluator.CheckResults{
+			{
+				CheckResult: conftest.CheckResult{
+					FileName: "/path/to/pipeline.json",
+					Failures: []conftest.Result{
+						{Message: "out of spam!", Metadata: map[string]interface{}{"code": "spam.stock"}},
+					},
+					Warnings: []conftest.Result{
+						{Message: "running low in spam", Metadata: map[string]interface{}{"code": "spam.stock"}},
+					},
+				},
+			},
+		},
+	})
+
+	fs := afero.NewMemMapFs()
+	parser := format.NewTargetParser("ignored", nil, fs)
+	assert.NoError(t, r.Write("sarif=out.sarif", parser))
+
+	actualText, err := afero.ReadFile(fs, "out.sarif")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": [{
+			"tool": {"driver": {
+				"name": "ec",
+				"version": "development",
+				"informationUri": "https://enterprisecontract.dev",
+				"rules": [{"id": "spam.stock"}]
+			}},
+			"artifacts": [{"location": {"uri": "/path/to/pipeline.json"}}],
+			"results": [
+				{
+					"ruleId": "spam.stock",
+					"ruleIndex": 0,
+					"level": "error",
+					"message": {"text": "out of spam!"},
+					"locations": [{"physicalLocation": {"artifactLocation": {"uri": "/path/to/pipeline.json", "index": 0}}}]
+				},
+				{
+					"ruleId": "spam.stock",
+					"ruleIndex": 0,
+					"level": "warning",
+					"message": {"text": "running low in spam"},
+					"locations": [{"physicalLocation": {"artifactLocation": {"uri": "/path/to/pipeline.json", "index": 0}}}]
+				}
+			]
+		}]
+	}`, string(actualText))
+}
+
+func TestReportSuccesses(t *testing.T) {
+	o := output.Output{
+		PolicyCheck: evaluator.CheckResults{
+			{
+				CheckResult: conftest.CheckResult{
+					FileName:  "/path/to/pipeline.json",
+					Successes: []conftest.Result{{Message: "Pass", Metadata: map[string]interface{}{"code": "spam.stock"}}},
+				},
+			},
+		},
+	}
+
+	hidden := NewReport(false)
+	hidden.Add(o)
+	assert.Empty(t, hidden.Definitions[0].Successes)
+
+	shown := NewReport(true)
+	shown.Add(o)
+	assert.Equal(t, []conftest.Result{{Message: "Pass", Metadata: map[string]interface{}{"code": "spam.stock"}}}, shown.Definitions[0].Successes)
+}
+
+func TestReportSummary(t *testing.T) {
+	r := NewReport(true)
+	r.Add(output.Output{
+		PolicyCheck: evaluator.CheckResults{
+			{
+				CheckResult: conftest.CheckResult{
+					FileName: "/path/to/task.yaml",
+					Failures: []conftest.Result{
+						{Message: "out of spam!", Metadata: map[string]interface{}{"code": "spam.stock"}},
+						{Message: "still out of spam!", Metadata: map[string]interface{}{"code": "spam.stock"}},
+					},
+				},
+			},
+			{
+				CheckResult: conftest.CheckResult{
+					FileName:  "/path/to/pipeline.yaml",
+					Successes: []conftest.Result{{Message: "Pass", Metadata: map[string]interface{}{"code": "spam.stock"}}},
+				},
+			},
+		},
+	})
+
+	fs := afero.NewMemMapFs()
+	parser := format.NewTargetParser("ignored", nil, fs)
+	assert.NoError(t, r.Write("summary=out.json", parser))
+
+	actualText, err := afero.ReadFile(fs, "out.json")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"definitions": [
+			{
+				"filename": "/path/to/pipeline.yaml",
+				"success": true,
+				"violations": {},
+				"warnings": {},
+				"successes": {"spam.stock": ["Pass"]},
+				"total_violations": 0,
+				"total_warnings": 0,
+				"total_successes": 1
+			},
+			{
+				"filename": "/path/to/task.yaml",
+				"success": false,
+				"violations": {"spam.stock": ["out of spam!", "There are 1 more \"spam.stock\" messages"]},
+				"warnings": {},
+				"successes": {},
+				"total_violations": 2,
+				"total_warnings": 0,
+				"total_successes": 0
+			}
+		],
+		"success": false
+	}`, string(actualText))
+}
+
+func TestReportJUnit(t *testing.T) {
+	r := NewReport(true)
+	r.Add(output.Output{
+		PolicyCheck: evaluator.CheckResults{
+			{
+				CheckResult: conftest.CheckResult{
+					FileName:  "/path/to/pipeline.yaml",
+					Failures:  []conftest.Result{{Message: "out of spam!", Metadata: map[string]interface{}{"code": "spam.stock"}}},
+					Warnings:  []conftest.Result{{Message: "running low in spam", Metadata: map[string]interface{}{"code": "spam.low"}}},
+					Successes: []conftest.Result{{Message: "Pass", Metadata: map[string]interface{}{"code": "spam.tasty"}}},
+				},
+			},
+		},
+	})
+
+	suites := r.toJUnit()
+	assert.Equal(t, 3, suites.Tests)
+	assert.Equal(t, 1, suites.Failures)
+	assert.Equal(t, 1, suites.Skipped)
+
+	suite := suites.Suites[0]
+	assert.Equal(t, "/path/to/pipeline.yaml", suite.Name)
+	assert.Equal(t, "spam.tasty: Pass", suite.Testcases[0].Name)
+	assert.Equal(t, "out of spam!", suite.Testcases[1].Failure.Message)
+	assert.Equal(t, "running low in spam", suite.Testcases[2].Skipped.Message)
+}
@@ -19,14 +19,14 @@ package definition
 import (
 	"context"
 	"fmt"
-	"path/filepath"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 
 	"github.com/enterprise-contract/ec-cli/internal/evaluation_target/definition"
+	"github.com/enterprise-contract/ec-cli/internal/evaluator"
 	"github.com/enterprise-contract/ec-cli/internal/output"
-	"github.com/enterprise-contract/ec-cli/internal/policy/source"
+	"github.com/enterprise-contract/ec-cli/internal/policy"
 	"github.com/enterprise-contract/ec-cli/internal/utils"
 )
 
@@ -34,28 +34,67 @@ var definitionFile = definition.NewDefinition
 
 // ValidatePipeline calls NewPipelineEvaluator to obtain an PipelineEvaluator. It then executes the associated TestRunner
 // which tests the associated pipeline file(s) against the associated policies, and displays the output.
-func ValidateDefinition(ctx context.Context, fpath string, sources []source.PolicySource, namespace []string) (*output.Output, error) {
+// The metadata of the rules, e.g. title and description, is kept in the results if detailed is true.
+func ValidateDefinition(ctx context.Context, fpath string, p policy.Policy, namespace []string, detailed bool) (*output.Output, error) {
 	defFiles, err := detectInput(ctx, fpath)
 	if err != nil {
 		return nil, err
 	}
-	p, err := definitionFile(ctx, defFiles, sources, namespace)
+
+	// Each document of multi-document YAML files is evaluated, and reported,
+	// separately
+	documents, err := splitDocuments(ctx, defFiles)
 	if err != nil {
-		log.Debug("Failed to create definition file!")
 		return nil, err
 	}
 
-	results, _, err := p.Evaluator.Evaluate(ctx, defFiles)
+	fs := utils.FS(ctx)
+	paths := make([]string, 0, len(documents))
+	names := make(map[string]string, len(documents))
+	for _, doc := range documents {
+		paths = append(paths, doc.path)
+		names[doc.path] = doc.name
+		if doc.path != doc.name {
+			defer fs.Remove(doc.path) //nolint:errcheck
+		}
+	}
+
+	d, err := definitionFile(ctx, paths, p, namespace)
 	if err != nil {
-		log.Debug("Problem running conftest policy check!")
+		log.Debug("Failed to create definition file!")
 		return nil, err
 	}
+
+	for _, e := range d.Evaluators {
+		defer e.Destroy()
+	}
+
+	allResults := evaluator.CheckResults{}
+	for _, e := range d.Evaluators {
+		results, _, err := e.Evaluate(ctx, paths)
+		if err != nil {
+			log.Debug("Problem running conftest policy check!")
+			return nil, err
+		}
+		allResults = append(allResults, results...)
+	}
+
+	for i := range allResults {
+		if name, ok := names[allResults[i].FileName]; ok {
+			allResults[i].FileName = name
+		}
+	}
+
 	log.Debug("Conftest policy check complete")
-	return &output.Output{PolicyCheck: results}, nil
+	out := &output.Output{Detailed: detailed}
+	out.SetPolicyCheck(allResults)
+
+	return out, nil
 }
 
-// detect if a file or directory was passed. if a directory, gather all files in it
-// the order is file lookup, json lookup then yaml
+// detect if a file, directory or glob pattern was passed. if a directory, gather
+// all JSON and YAML files in it and its subdirectories. the order is json
+// lookup, yaml lookup, glob lookup then file lookup
 func detectInput(ctx context.Context, fpath string) ([]string, error) {
 	if utils.IsJson(fpath) {
 		log.Debug("valid JSON found for definition file")
@@ -71,6 +110,11 @@ func detectInput(ctx context.Context, fpath string) ([]string, error) {
 	}
 	log.Debug("unable to detect input as YAML")
 
+	if isGlob(fpath) {
+		log.Debugf("looking up definition files matching %v", fpath)
+		return globLookup(ctx, fpath)
+	}
+
 	fileExists, err := utils.IsFile(ctx, fpath)
 	if err != nil {
 		return nil, err
@@ -85,7 +129,8 @@ func detectInput(ctx context.Context, fpath string) ([]string, error) {
 }
 
 // if a single file is provided, return it
-// if the file is a directory, return the files inside the directory
+// if the file is a directory, return the JSON and YAML files inside the
+// directory and its subdirectories, except for the excluded ones
 func fileLookup(ctx context.Context, path string) ([]string, error) {
 	fs := utils.FS(ctx)
 	var defFiles []string
@@ -103,7 +148,10 @@ func fileLookup(ctx context.Context, path string) ([]string, error) {
 	}
 
 	if dir {
-		files, err := afero.ReadDir(fs, path)
+		exclude := excludePatterns(ctx)
+		files, err := utils.WalkJsonOrYamlFiles(fs, path, func(fpath string) bool {
+			return !excluded(exclude, fpath)
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -112,9 +160,7 @@ func fileLookup(ctx context.Context, path string) ([]string, error) {
 			return nil, fmt.Errorf("the directory %v contained no files", path)
 		}
 
-		for _, f := range files {
-			defFiles = append(defFiles, filepath.Join(path, f.Name()))
-		}
+		defFiles = files
 	} else {
 		defFiles = append(defFiles, path)
 	}
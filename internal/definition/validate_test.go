@@ -29,7 +29,7 @@ import (
 	"github.com/enterprise-contract/ec-cli/internal/evaluation_target/definition"
 	"github.com/enterprise-contract/ec-cli/internal/evaluator"
 	"github.com/enterprise-contract/ec-cli/internal/output"
-	"github.com/enterprise-contract/ec-cli/internal/policy/source"
+	"github.com/enterprise-contract/ec-cli/internal/policy"
 	"github.com/enterprise-contract/ec-cli/internal/utils"
 )
 
@@ -58,15 +58,15 @@ func (e badMockEvaluator) CapabilitiesPath() string {
 	return ""
 }
 
-func mockNewPipelineDefinitionFile(ctx context.Context, fpath []string, sources []source.PolicySource, namespace []string) (*definition.Definition, error) {
+func mockNewPipelineDefinitionFile(ctx context.Context, fpath []string, p policy.Policy, namespace []string) (*definition.Definition, error) {
 	return &definition.Definition{
-		Evaluator: mockEvaluator{},
+		Evaluators: []evaluator.Evaluator{mockEvaluator{}},
 	}, nil
 }
 
-func badMockNewPipelineDefinitionFile(ctx context.Context, fpath []string, sources []source.PolicySource, namespace []string) (*definition.Definition, error) {
+func badMockNewPipelineDefinitionFile(ctx context.Context, fpath []string, p policy.Policy, namespace []string) (*definition.Definition, error) {
 	return &definition.Definition{
-		Evaluator: badMockEvaluator{},
+		Evaluators: []evaluator.Evaluator{badMockEvaluator{}},
 	}, nil
 }
 
@@ -81,7 +81,7 @@ func Test_ValidatePipeline(t *testing.T) {
 		fpath   string
 		err     error
 		output  *output.Output
-		defFunc func(ctx context.Context, fpath []string, sources []source.PolicySource, namespace []string) (*definition.Definition, error)
+		defFunc func(ctx context.Context, fpath []string, p policy.Policy, namespace []string) (*definition.Definition, error)
 	}{
 		{
 			name:    "validation succeeds",
@@ -143,10 +143,13 @@ func Test_ValidatePipeline(t *testing.T) {
 	assert.NoError(t, errFile)
 	ctx := utils.WithFS(context.Background(), appFS)
 
+	p, err := policy.NewOfflinePolicy(ctx, policy.Now)
+	assert.NoError(t, err)
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			definitionFile = tt.defFunc
-			output, err := ValidateDefinition(ctx, tt.fpath, []source.PolicySource{}, []string{})
+			output, err := ValidateDefinition(ctx, tt.fpath, p, []string{}, false)
 			assert.Equal(t, tt.err, err)
 			assert.Equal(t, tt.output, output)
 		})
@@ -22,16 +22,14 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"errors"
-	"fmt"
 	"os"
 	"path"
+	"time"
 
 	ecc "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
-	"github.com/in-toto/in-toto-golang/in_toto"
-	"github.com/qri-io/jsonschema"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
@@ -42,14 +40,14 @@ import (
 	"github.com/enterprise-contract/ec-cli/internal/policy"
 	"github.com/enterprise-contract/ec-cli/internal/policy/source"
 	"github.com/enterprise-contract/ec-cli/internal/utils"
+	"github.com/enterprise-contract/ec-cli/internal/vsa"
 	ece "github.com/enterprise-contract/ec-cli/pkg/error"
-	"github.com/enterprise-contract/ec-cli/pkg/schema"
 )
 
 var (
 	EV001 = ece.NewError("EV001", "No attestation data", ece.ErrorExitStatus)
 	EV002 = ece.NewError("EV002", "Unable to decode attestation data from attestation image", ece.ErrorExitStatus)
-	EV003 = ece.NewError("EV003", "Attestation syntax validation failed", ece.ErrorExitStatus)
+	EV004 = ece.NewError("EV004", "Unable to load attestation schema", ece.ErrorExitStatus)
 )
 
 var newConftestEvaluator = evaluator.NewConftestEvaluator
@@ -59,21 +57,33 @@ var newConftestEvaluator = evaluator.NewConftestEvaluator
 // equivalent to http.DefaultTransport, with a reduced timeout and keep-alive
 var imageRefTransport = remote.WithTransport(remote.DefaultTransport)
 
-var attestationSchemas = map[string]jsonschema.Schema{
-	"https://slsa.dev/provenance/v0.2": schema.SLSA_Provenance_v0_2,
-}
-
 // ApplicationSnapshotImage represents the structure needed to evaluate an Application Snapshot Image
 type ApplicationSnapshotImage struct {
 	reference    name.Reference
 	checkOpts    cosign.CheckOpts
 	signatures   []output.EntitySignature
-	attestations []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]
+	attestations []attestation.Attestation[any]
 	Evaluators   []evaluator.Evaluator
 }
 
 // NewApplicationSnapshotImage returns an ApplicationSnapshotImage struct with reference, checkOpts, and evaluator ready to use.
 func NewApplicationSnapshotImage(ctx context.Context, url string, p policy.Policy) (*ApplicationSnapshotImage, error) {
+	a, err := NewImage(url, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.LoadEvaluators(ctx, p); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// NewImage returns an ApplicationSnapshotImage struct with reference and
+// checkOpts ready to use, but without evaluators. Invoke LoadEvaluators, which
+// fetches the policy sources, once the image is to be evaluated.
+func NewImage(url string, p policy.Policy) (*ApplicationSnapshotImage, error) {
 	opts, err := p.CheckOpts()
 	if err != nil {
 		return nil, err
@@ -86,11 +96,17 @@ func NewApplicationSnapshotImage(ctx context.Context, url string, p policy.Polic
 		return nil, err
 	}
 
-	// Return an evaluator for each of these
-	for _, sourceGroup := range p.Spec().Sources {
-		// Todo: Make each fetch run concurrently
+	return a, nil
+}
+
+// LoadEvaluators fetches the policy sources of each source group of the
+// policy and adds an evaluator for each group.
+func (a *ApplicationSnapshotImage) LoadEvaluators(ctx context.Context, p policy.Policy) error {
+	// Return an evaluator for each of these, the evaluators are kept in the
+	// order of the source groups regardless of the order of their completion
+	evaluators, err := utils.ParallelMap(ctx, p.Spec().Sources, func(ctx context.Context, sourceGroup ecc.Source) (evaluator.Evaluator, error) {
 		log.Debugf("Fetching policy source group '%s'", sourceGroup.Name)
-		policySources, err := fetchPolicySources(sourceGroup)
+		policySources, err := source.FetchPolicySources(sourceGroup)
 		if err != nil {
 			log.Debugf("Failed to fetch policy source group '%s'!", sourceGroup.Name)
 			return nil, err
@@ -107,31 +123,16 @@ func NewApplicationSnapshotImage(ctx context.Context, url string, p policy.Polic
 		}
 
 		log.Debug("Conftest evaluator initialized")
-		a.Evaluators = append(a.Evaluators, c)
-	}
-	return a, nil
-}
-
-// fetchPolicySources returns an array of policy sources
-func fetchPolicySources(s ecc.Source) ([]source.PolicySource, error) {
-	policySources := make([]source.PolicySource, 0, len(s.Policy)+len(s.Data))
-
-	for _, policySourceUrl := range s.Policy {
-		url := source.PolicyUrl{Url: policySourceUrl, Kind: "policy"}
-		policySources = append(policySources, &url)
-	}
-
-	for _, dataSourceUrl := range s.Data {
-		url := source.PolicyUrl{Url: dataSourceUrl, Kind: "data"}
-		policySources = append(policySources, &url)
-	}
-
-	if s.RuleData != nil {
-		data := append(append([]byte(`{"rule_data__configuration__":`), s.RuleData.Raw...), '}')
-		policySources = append(policySources, source.InlineData(data))
+		return c, nil
+	})
+	if err != nil {
+		// release the work directories of the evaluators that were built
+		evaluator.DestroyAll(evaluators)
+		return err
 	}
+	a.Evaluators = append(a.Evaluators, evaluators...)
 
-	return policySources, nil
+	return nil
 }
 
 // ValidateImageAccess executes the remote.Head method on the ApplicationSnapshotImage image ref
@@ -163,7 +164,7 @@ func (a *ApplicationSnapshotImage) SetImageURL(url string) error {
 	a.reference = ref
 
 	// Reset internal state relevant to the image
-	a.attestations = []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]{}
+	a.attestations = []attestation.Attestation[any]{}
 	a.signatures = []output.EntitySignature{}
 
 	return nil
@@ -223,6 +224,31 @@ func (a *ApplicationSnapshotImage) ValidateImageSignature(ctx context.Context) e
 	return nil
 }
 
+// VerificationSummary looks for the most recent Verification Summary
+// Attestation, signed by the key trusted by the checker, recording that the
+// image passed the validation against the policy with the given digest. The
+// time of the verification is returned, found is false if there is no such
+// attestation.
+func (a *ApplicationSnapshotImage) VerificationSummary(ctx context.Context, checker *vsa.Checker, policyDigest string) (timeVerified time.Time, found bool) {
+	ref, ok := a.reference.(name.Digest)
+	if !ok {
+		log.Debugf("Not looking for a Verification Summary Attestation of %s, a digest is required", a.reference)
+		return
+	}
+
+	opts := checker.CheckOpts()
+	opts.RegistryClientOpts = a.checkOpts.RegistryClientOpts
+	attestations, _, err := NewClient(ctx).VerifyImageAttestations(ctx, ref, opts)
+	if err != nil {
+		log.Debugf("No trusted Verification Summary Attestation found: %v", err)
+		return
+	}
+
+	predicate, found := checker.Find(attestations, ref, policyDigest, time.Now())
+
+	return predicate.TimeVerified, found
+}
+
 // ValidateAttestationSignature executes the cosign.VerifyImageAttestations method
 func (a *ApplicationSnapshotImage) ValidateAttestationSignature(ctx context.Context) error {
 	// Set the ClaimVerifier on a shallow *copy* of CheckOpts to avoid unexpected side-effects
@@ -237,71 +263,78 @@ func (a *ApplicationSnapshotImage) ValidateAttestationSignature(ctx context.Cont
 	// the signatures do exist in the expected format.
 
 	for _, att := range layers {
-		sp, err := attestation.SLSAProvenanceFromLayer(att)
+		at, err := attestation.FromLayer(att)
 		if err != nil {
-			log.Debugf("Ignoring non SLSA Provenance attestation: %s", err)
+			log.Debugf("Ignoring non in-toto attestation: %s", err)
 			continue
 		}
-		a.attestations = append(a.attestations, sp)
+		log.Debugf("Found attestation with predicate type: %s", at.PredicateType())
+		a.attestations = append(a.attestations, at)
 	}
 	return nil
 }
 
-// ValidateAttestationSyntax validates the attestations against known JSON
-// schemas, errors out if there are no attestations to check to prevent
-// sucessful syntax check of no inputs, must invoke
-// [ValidateAttestationSignature] to prefill the attestations.
-func (a ApplicationSnapshotImage) ValidateAttestationSyntax(ctx context.Context) error {
-	if len(a.attestations) == 0 {
-		log.Debug("No attestation data found, possibly due to attestation image signature not being validated beforehand")
-		return EV001
+// AttestationSyntaxChecks validates each attestation against the JSON schema
+// registered for its predicate type and returns the outcome per attestation.
+// Attestations of predicate types without a known JSON schema are skipped.
+// Errors out if there is no SLSA Provenance attestation to check, regardless
+// of other attestations, must invoke [ValidateAttestationSignature] to
+// prefill the attestations.
+func (a ApplicationSnapshotImage) AttestationSyntaxChecks(ctx context.Context) ([]output.SyntaxCheck, error) {
+	if len(a.ProvenanceAttestations()) == 0 {
+		log.Debug("No SLSA Provenance attestation data found, possibly due to attestation image signature not being validated beforehand")
+		return nil, EV001
 	}
 
-	allErrors := map[string][]jsonschema.KeyError{}
-	for _, sp := range a.attestations {
-		// at least one of the schemas needs to pass validation
-		for id, schema := range attestationSchemas {
-			if errs, err := schema.ValidateBytes(ctx, sp.Data()); err != nil {
-				return EV002.CausedBy(err)
-			} else {
-				if len(errs) == 0 {
-					// one schema validation suceeded, consider this a success
-					// TODO: one possible drawback of this is that JSON schemas
-					// are open by default, e.g. if additionalProperties=true
-					// (the default) properties not defined in the schema are
-					// allowed, which in turn means that the document might not
-					// contain any of the properties declared in the schema
-					continue
-				}
-
-				allErrors[id] = errs
-				log.Debugf("Validated the statement against %s schema and found the following errors: %v", id, errs)
-			}
+	checks := make([]output.SyntaxCheck, 0, len(a.attestations))
+	for _, att := range a.attestations {
+		predicateType := att.PredicateType()
+		s := schemaFor(ctx, predicateType)
+		if s == nil {
+			log.Debugf("No schema known for the predicate type %s, skipping syntax validation", predicateType)
+			continue
 		}
-	}
 
-	if len(allErrors) == 0 {
-		// TODO another option might be to filter out invalid statement JSONs
-		// and keep only the valid ones
-		return nil
-	}
+		// schemas are registered by predicate type so that doubles as
+		// the schema identifier
+		id := predicateType
+		errs, err := s.ValidateBytes(ctx, att.Data())
+		if err != nil {
+			return nil, EV002.CausedBy(err)
+		}
 
-	log.Debug("Failed to validate statements from the attastation image against all known schemas")
-	msg := ""
-	for id, errs := range allErrors {
-		msg += fmt.Sprintf("\nSchema ID: %s", id)
+		check := output.SyntaxCheck{PredicateType: predicateType, SchemaID: id}
 		for _, e := range errs {
-			msg += fmt.Sprintf("\n - %s", e.Error())
+			check.Errors = append(check.Errors, e.Error())
+		}
+		if len(errs) > 0 {
+			log.Debugf("Validated the statement against %s schema and found the following errors: %v", id, errs)
 		}
+
+		checks = append(checks, check)
 	}
-	return EV003.CausedBy(errors.New(msg))
+
+	return checks, nil
 }
 
 // Attestations returns the value of the attestations field of the ApplicationSnapshotImage struct
-func (a *ApplicationSnapshotImage) Attestations() []attestation.Attestation[in_toto.ProvenanceStatementSLSA02] {
+func (a *ApplicationSnapshotImage) Attestations() []attestation.Attestation[any] {
 	return a.attestations
 }
 
+// ProvenanceAttestations returns the SLSA Provenance attestations, of any of
+// the supported versions, of the ApplicationSnapshotImage
+func (a ApplicationSnapshotImage) ProvenanceAttestations() []attestation.Attestation[any] {
+	var provenance []attestation.Attestation[any]
+	for _, att := range a.attestations {
+		if attestation.IsSLSAProvenance(att.PredicateType()) {
+			provenance = append(provenance, att)
+		}
+	}
+
+	return provenance
+}
+
 func (a *ApplicationSnapshotImage) Signatures() []output.EntitySignature {
 	return a.signatures
 }
@@ -310,9 +343,16 @@ func (a *ApplicationSnapshotImage) Signatures() []output.EntitySignature {
 func (a *ApplicationSnapshotImage) WriteInputFile(ctx context.Context) (string, error) {
 	log.Debugf("Attempting to write %d attestations to input file", len(a.attestations))
 
-	var statements []in_toto.ProvenanceStatementSLSA02
-	for _, sp := range a.attestations {
-		statements = append(statements, sp.Statement())
+	// Statements of all predicate types are provided to the policy, rules can
+	// tell them apart by the predicateType attribute
+	var statements []any
+	for _, att := range a.attestations {
+		statement, err := inputAttestation(att)
+		if err != nil {
+			log.Debug("Problem preparing attestation for the input file!")
+			return "", err
+		}
+		statements = append(statements, statement)
 	}
 
 	type Image struct {
@@ -321,8 +361,8 @@ func (a *ApplicationSnapshotImage) WriteInputFile(ctx context.Context) (string,
 	}
 
 	input := struct {
-		Attestations []in_toto.ProvenanceStatementSLSA02 `json:"attestations"`
-		Image        Image                               `json:"image"`
+		Attestations []any `json:"attestations"`
+		Image        Image `json:"image"`
 	}{
 		Attestations: statements,
 		Image: Image{
@@ -357,3 +397,30 @@ func (a *ApplicationSnapshotImage) WriteInputFile(ctx context.Context) (string,
 	log.Debugf("Done preparing input file:\n%s", inputJSONPath)
 	return inputJSONPath, nil
 }
+
+// inputAttestation returns the representation of the attestation within the
+// policy input: the attributes of the in-toto statement, along with the
+// signatures of the attestation, if any, under the "signatures" attribute.
+// This allows policy rules to assert on who signed the attestation.
+func inputAttestation(att attestation.Attestation[any]) (any, error) {
+	statement := att.Statement()
+
+	signatures := att.Signatures()
+	if len(signatures) == 0 {
+		return statement, nil
+	}
+
+	data, err := json.Marshal(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := map[string]any{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+
+	obj["signatures"] = signatures
+
+	return obj, nil
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/in-toto/in-toto-golang/in_toto"
 	"github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/common"
 	v02 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
+	slsa1 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v1"
 	"github.com/secure-systems-lab/go-securesystemslib/dsse"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/oci"
@@ -61,7 +62,7 @@ func TestApplicationSnapshotImage_ValidateImageAccess(t *testing.T) {
 	type fields struct {
 		reference    name.Reference
 		checkOpts    cosign.CheckOpts
-		attestations []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]
+		attestations []attestation.Attestation[any]
 		Evaluator    evaluator.Evaluator
 	}
 	type args struct {
@@ -129,6 +130,10 @@ func (f fakeAtt) Data() []byte {
 	return bytes
 }
 
+func (f fakeAtt) PredicateType() string {
+	return f.statement.PredicateType
+}
+
 func (f fakeAtt) Statement() in_toto.ProvenanceStatementSLSA02 {
 	return f.statement
 }
@@ -137,7 +142,7 @@ func (f fakeAtt) Signatures() []output.EntitySignature {
 	return nil
 }
 
-func createSimpleAttestation(statement *in_toto.ProvenanceStatementSLSA02) attestation.Attestation[in_toto.ProvenanceStatementSLSA02] {
+func createSimpleAttestation(statement *in_toto.ProvenanceStatementSLSA02) attestation.Attestation[any] {
 	if statement == nil {
 		statement = &in_toto.ProvenanceStatementSLSA02{
 			StatementHeader: in_toto.StatementHeader{
@@ -150,9 +155,65 @@ func createSimpleAttestation(statement *in_toto.ProvenanceStatementSLSA02) attes
 		}
 	}
 
-	return fakeAtt{statement: *statement}
+	return attestation.Untyped[in_toto.ProvenanceStatementSLSA02](fakeAtt{statement: *statement})
+}
+
+type fakeAttV1 struct {
+	statement in_toto.ProvenanceStatementSLSA1
+}
+
+func (f fakeAttV1) Data() []byte {
+	bytes, err := json.Marshal(f.statement)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
 }
 
+func (f fakeAttV1) PredicateType() string {
+	return f.statement.PredicateType
+}
+
+func (f fakeAttV1) Statement() in_toto.ProvenanceStatementSLSA1 {
+	return f.statement
+}
+
+func (f fakeAttV1) Signatures() []output.EntitySignature {
+	return nil
+}
+
+func createSimpleAttestationV1() attestation.Attestation[any] {
+	return attestation.Untyped[in_toto.ProvenanceStatementSLSA1](fakeAttV1{statement: in_toto.ProvenanceStatementSLSA1{
+		StatementHeader: in_toto.StatementHeader{
+			Type:          attestation.StatementInTotoV1,
+			PredicateType: slsa1.PredicateSLSAProvenance,
+		},
+		Predicate: slsa1.ProvenancePredicate{
+			BuildDefinition: slsa1.ProvenanceBuildDefinition{
+				BuildType: pipelineRunBuildType,
+			},
+		},
+	}})
+}
+
+const simpleAttestationV1JSONText = `{
+	"_type": "https://in-toto.io/Statement/v1",
+	"predicateType": "https://slsa.dev/provenance/v1",
+	"subject": null,
+	"predicate": {
+		"buildDefinition": {
+			"buildType": "https://tekton.dev/attestations/chains/pipelinerun@v2",
+			"externalParameters": null
+		},
+		"runDetails": {
+			"builder": {
+				"id": ""
+			},
+			"metadata": {}
+		}
+	}
+}`
+
 const simpleAttestationJSONText = `{
 	"_type": "https://in-toto.io/Statement/v0.1",
 	"predicateType": "https://slsa.dev/provenance/v0.2",
@@ -178,7 +239,7 @@ func TestWriteInputFile(t *testing.T) {
 			name: "single attestations",
 			snapshot: ApplicationSnapshotImage{
 				reference:    name.MustParseReference("registry.io/repository/image:tag"),
-				attestations: []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]{createSimpleAttestation(nil)},
+				attestations: []attestation.Attestation[any]{createSimpleAttestation(nil)},
 			},
 			want: `{"attestations": [` + simpleAttestationJSONText + `], "image": {"ref": "registry.io/repository/image:tag"}}`,
 		},
@@ -186,13 +247,24 @@ func TestWriteInputFile(t *testing.T) {
 			name: "multiple attestations",
 			snapshot: ApplicationSnapshotImage{
 				reference: name.MustParseReference("registry.io/repository/image:tag"),
-				attestations: []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]{
+				attestations: []attestation.Attestation[any]{
 					createSimpleAttestation(nil),
 					createSimpleAttestation(nil),
 				},
 			},
 			want: `{"attestations": [` + simpleAttestationJSONText + "," + simpleAttestationJSONText + `], "image": {"ref": "registry.io/repository/image:tag"}}`,
 		},
+		{
+			name: "SLSA Provenance v0.2 and v1.0 attestations",
+			snapshot: ApplicationSnapshotImage{
+				reference: name.MustParseReference("registry.io/repository/image:tag"),
+				attestations: []attestation.Attestation[any]{
+					createSimpleAttestation(nil),
+					createSimpleAttestationV1(),
+				},
+			},
+			want: `{"attestations": [` + simpleAttestationJSONText + "," + simpleAttestationV1JSONText + `], "image": {"ref": "registry.io/repository/image:tag"}}`,
+		},
 		{
 			name: "image signatures",
 			snapshot: ApplicationSnapshotImage{
@@ -208,7 +280,7 @@ func TestWriteInputFile(t *testing.T) {
 						Signature: "signature2",
 					},
 				},
-				attestations: []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]{
+				attestations: []attestation.Attestation[any]{
 					createSimpleAttestation(nil),
 				},
 			},
@@ -240,7 +312,7 @@ func TestWriteInputFileMultipleAttestations(t *testing.T) {
 	att := createSimpleAttestation(nil)
 	a := ApplicationSnapshotImage{
 		reference:    name.MustParseReference("registry.io/repository/image:tag"),
-		attestations: []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]{att},
+		attestations: []attestation.Attestation[any]{att},
 	}
 
 	fs := afero.NewMemMapFs()
@@ -262,7 +334,7 @@ func TestWriteInputFileMultipleAttestations(t *testing.T) {
 func TestSyntaxValidationWithoutAttestations(t *testing.T) {
 	noAttestations := ApplicationSnapshotImage{}
 
-	err := noAttestations.ValidateAttestationSyntax(context.TODO())
+	_, err := noAttestations.AttestationSyntaxChecks(context.TODO())
 	assert.Error(t, err, "Expected error in validation")
 
 	assert.True(t, strings.HasPrefix(err.Error(), "EV001: No attestation data"))
@@ -313,36 +385,47 @@ func TestSyntaxValidation(t *testing.T) {
 
 	cases := []struct {
 		name         string
-		attestations []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]
+		attestations []attestation.Attestation[any]
+		errors       [][]string
 		err          *regexp.Regexp
 	}{
 		{
 			name: "invalid",
-			attestations: []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]{
+			attestations: []attestation.Attestation[any]{
 				invalid,
 			},
-			err: regexp.MustCompile(`EV003: Attestation syntax validation failed, .*, caused by:\nSchema ID: https://slsa.dev/provenance/v0.2\n - /predicate/builder/id: "invalid" invalid uri: uri missing scheme prefix`),
+			errors: [][]string{
+				{`/predicate/builder/id: "invalid" invalid uri: uri missing scheme prefix`},
+			},
 		},
 		{
 			name: "valid",
-			attestations: []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]{
+			attestations: []attestation.Attestation[any]{
 				valid,
 			},
+			errors: [][]string{nil},
 		},
 		{
 			name: "empty",
-			attestations: []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]{
-				createSimpleAttestation(&in_toto.ProvenanceStatementSLSA02{}),
+			attestations: []attestation.Attestation[any]{
+				createSimpleAttestation(&in_toto.ProvenanceStatementSLSA02{
+					StatementHeader: in_toto.StatementHeader{
+						PredicateType: v02.PredicateSLSAProvenance,
+					},
+				}),
 			},
 			err: regexp.MustCompile(`EV002: Unable to decode attestation data from attestation image, .*, caused by: unexpected end of JSON input`),
 		},
 		{
 			name: "valid and invalid",
-			attestations: []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]{
+			attestations: []attestation.Attestation[any]{
 				valid,
 				invalid,
 			},
-			err: regexp.MustCompile(`EV003`),
+			errors: [][]string{
+				nil,
+				{`/predicate/builder/id: "invalid" invalid uri: uri missing scheme prefix`},
+			},
 		},
 	}
 
@@ -352,12 +435,17 @@ func TestSyntaxValidation(t *testing.T) {
 				attestations: c.attestations,
 			}
 
-			err := a.ValidateAttestationSyntax(context.TODO())
+			checks, err := a.AttestationSyntaxChecks(context.TODO())
 			if c.err == nil {
-				assert.NoError(t, err)
+				require.NoError(t, err)
+				require.Len(t, checks, len(c.errors))
+				for i, check := range checks {
+					assert.Equal(t, v02.PredicateSLSAProvenance, check.SchemaID)
+					assert.Equal(t, c.errors[i], check.Errors)
+				}
 			} else {
 				assert.Error(t, err)
-				assert.Regexp(t, err, err.Error())
+				assert.Regexp(t, c.err, err.Error())
 			}
 		})
 	}
@@ -671,3 +759,109 @@ func TestValidateImageSignatureWithCertificates(t *testing.T) {
 
 	snaps.MatchSnapshot(t, a.signatures)
 }
+
+func TestValidateAttestationSignatureAllPredicateTypes(t *testing.T) {
+	ref := name.MustParseReference("registry.io/repository/image:tag")
+	a := ApplicationSnapshotImage{
+		reference: ref,
+	}
+
+	layer := func(statement string) oci.Signature {
+		payload := `{"payload":"` + base64.StdEncoding.EncodeToString([]byte(statement)) + `","signatures":[{"keyid":"key-id","sig":"sig"}]}`
+		signature, err := static.NewSignature([]byte(payload), "signature", static.WithLayerMediaType(types.MediaType(cosignTypes.DssePayloadType)))
+		require.NoError(t, err)
+
+		return signature
+	}
+
+	notDSSE, err := static.NewSignature([]byte(`{}`), "signature")
+	require.NoError(t, err)
+
+	c := MockClient{}
+	ctx := WithClient(context.Background(), &c)
+	c.On("VerifyImageAttestations", ctx, ref, mock.Anything).Return([]oci.Signature{
+		layer(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://slsa.dev/provenance/v0.2","predicate":{}}`),
+		layer(`{"_type":"https://in-toto.io/Statement/v1","predicateType":"https://slsa.dev/provenance/v1","predicate":{}}`),
+		layer(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://spdx.dev/Document","predicate":{"spdxVersion":"SPDX-2.3"}}`),
+		layer(`{"_type":"https://in-toto.io/Statement/v0.1","predicateType":"https://cyclonedx.org/bom","predicate":{"bomFormat":"CycloneDX"}}`),
+		notDSSE,
+	}, false, nil)
+
+	require.NoError(t, a.ValidateAttestationSignature(ctx))
+
+	predicateTypes := make([]string, 0, len(a.Attestations()))
+	for _, att := range a.Attestations() {
+		predicateTypes = append(predicateTypes, att.PredicateType())
+	}
+
+	assert.Equal(t, []string{
+		"https://slsa.dev/provenance/v0.2",
+		"https://slsa.dev/provenance/v1",
+		"https://spdx.dev/Document",
+		"https://cyclonedx.org/bom",
+	}, predicateTypes)
+
+	fs := afero.NewMemMapFs()
+	ctx = utils.WithFS(ctx, fs)
+	a.reference = name.MustParseReference("registry.io/repository/image:tag")
+	a.attestations = a.attestations[2:3]
+
+	input, err := a.WriteInputFile(ctx)
+	require.NoError(t, err)
+
+	bytes, err := afero.ReadFile(fs, input)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"attestations": [{
+			"_type": "https://in-toto.io/Statement/v0.1",
+			"predicateType": "https://spdx.dev/Document",
+			"subject": null,
+			"predicate": {"spdxVersion": "SPDX-2.3"},
+			"signatures": [{
+				"keyid": "key-id",
+				"sig": "sig",
+				"metadata": {
+					"predicateType": "https://spdx.dev/Document",
+					"type": "https://in-toto.io/Statement/v0.1"
+				}
+			}]
+		}],
+		"image": {"ref": "registry.io/repository/image:tag"}
+	}`, string(bytes))
+}
+
+func TestSyntaxValidationSkipsUnknownPredicateTypes(t *testing.T) {
+	sbom := createSimpleAttestation(&in_toto.ProvenanceStatementSLSA02{
+		StatementHeader: in_toto.StatementHeader{
+			Type:          in_toto.StatementInTotoV01,
+			PredicateType: "https://spdx.dev/Document",
+		},
+	})
+
+	a := ApplicationSnapshotImage{
+		attestations: []attestation.Attestation[any]{createSimpleAttestation(nil), sbom},
+	}
+
+	checks, err := a.AttestationSyntaxChecks(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, checks, 1)
+	assert.Equal(t, v02.PredicateSLSAProvenance, checks[0].PredicateType)
+}
+
+func TestSyntaxValidationRequiresProvenance(t *testing.T) {
+	sbom := createSimpleAttestation(&in_toto.ProvenanceStatementSLSA02{
+		StatementHeader: in_toto.StatementHeader{
+			Type:          in_toto.StatementInTotoV01,
+			PredicateType: "https://spdx.dev/Document",
+		},
+	})
+
+	a := ApplicationSnapshotImage{
+		attestations: []attestation.Attestation[any]{sbom},
+	}
+
+	assert.Len(t, a.ProvenanceAttestations(), 0)
+
+	_, err := a.AttestationSyntaxChecks(context.TODO())
+	assert.ErrorContains(t, err, "EV001: No attestation data")
+}
@@ -0,0 +1,109 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application_snapshot_image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/qri-io/jsonschema"
+	"github.com/spf13/afero"
+
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+	"github.com/enterprise-contract/ec-cli/pkg/schema"
+)
+
+const attestationSchemasContextKey contextKey = "ec.application-snapshot-image.attestation-schemas"
+
+// attestationSchemas holds the built-in JSON schemas keyed by the predicate
+// type of the attestation they validate.
+var attestationSchemas = map[string]*jsonschema.Schema{
+	"https://slsa.dev/provenance/v0.2": &schema.SLSA_Provenance_v0_2,
+	"https://slsa.dev/provenance/v1":   &schema.SLSA_Provenance_v1,
+}
+
+// WithAttestationSchemas registers additional JSON schemas keyed by predicate
+// type to validate the attestations with. Schemas provided here take
+// precedence over the built-in schemas for the same predicate type.
+func WithAttestationSchemas(ctx context.Context, schemas map[string]*jsonschema.Schema) context.Context {
+	return context.WithValue(ctx, attestationSchemasContextKey, schemas)
+}
+
+// schemaFor returns the JSON schema used to validate attestations of the
+// given predicate type, or nil if no schema is known for it.
+func schemaFor(ctx context.Context, predicateType string) *jsonschema.Schema {
+	if custom, ok := ctx.Value(attestationSchemasContextKey).(map[string]*jsonschema.Schema); ok {
+		if s, ok := custom[predicateType]; ok {
+			return s
+		}
+	}
+
+	return attestationSchemas[predicateType]
+}
+
+// LoadAttestationSchema loads a JSON schema from the provided location, which
+// can either be a path to a local file or a HTTPS URL.
+func LoadAttestationSchema(ctx context.Context, location string) (*jsonschema.Schema, error) {
+	var data []byte
+	var err error
+	switch {
+	case strings.HasPrefix(location, "https://"):
+		data, err = fetchSchema(ctx, location)
+	case strings.HasPrefix(location, "http://"):
+		return nil, EV004.CausedByF("refusing to fetch schema over insecure transport: %s", location)
+	default:
+		data, err = afero.ReadFile(utils.FS(ctx), location)
+	}
+	if err != nil {
+		return nil, EV004.CausedBy(err)
+	}
+
+	s := jsonschema.Schema{}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, EV004.CausedByF("unable to parse schema from %s: %v", location, err)
+	}
+
+	return &s, nil
+}
+
+// schemaClient fetches the schemas, the timeout applies even if the context
+// has no deadline so that an unreachable URL does not hang the validation
+var schemaClient = &http.Client{Timeout: 30 * time.Second}
+
+func fetchSchema(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := schemaClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status fetching %s: %s", url, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
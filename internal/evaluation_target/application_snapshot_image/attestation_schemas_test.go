@@ -0,0 +1,118 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package application_snapshot_image
+
+import (
+	"context"
+	"testing"
+
+	"github.com/in-toto/in-toto-golang/in_toto"
+	v02 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
+	"github.com/qri-io/jsonschema"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/attestation"
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+const sbomSchema = `{
+	"$schema": "https://json-schema.org/draft/2019-09/schema",
+	"type": "object",
+	"required": ["predicate"],
+	"properties": {
+		"predicate": {
+			"type": "object",
+			"required": ["spdxVersion"]
+		}
+	}
+}`
+
+func TestAttestationSyntaxChecks(t *testing.T) {
+	valid := createSimpleAttestation(nil)
+	v1 := createSimpleAttestationV1()
+	sbom := createSimpleAttestation(&in_toto.ProvenanceStatementSLSA02{
+		StatementHeader: in_toto.StatementHeader{
+			Type:          in_toto.StatementInTotoV01,
+			PredicateType: "https://spdx.dev/Document",
+		},
+	})
+
+	a := ApplicationSnapshotImage{
+		attestations: []attestation.Attestation[any]{valid, v1, sbom},
+	}
+
+	checks, err := a.AttestationSyntaxChecks(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+
+	assert.Equal(t, v02.PredicateSLSAProvenance, checks[0].PredicateType)
+	assert.Equal(t, v02.PredicateSLSAProvenance, checks[0].SchemaID)
+	assert.Empty(t, checks[0].Errors)
+
+	assert.Equal(t, "https://slsa.dev/provenance/v1", checks[1].PredicateType)
+}
+
+func TestAttestationSyntaxChecksWithCustomSchema(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	ctx := utils.WithFS(context.Background(), fs)
+	require.NoError(t, afero.WriteFile(fs, "/sbom.json", []byte(sbomSchema), 0o400))
+
+	s, err := LoadAttestationSchema(ctx, "/sbom.json")
+	require.NoError(t, err)
+
+	ctx = WithAttestationSchemas(ctx, map[string]*jsonschema.Schema{
+		"https://spdx.dev/Document": s,
+	})
+
+	sbom := createSimpleAttestation(&in_toto.ProvenanceStatementSLSA02{
+		StatementHeader: in_toto.StatementHeader{
+			Type:          in_toto.StatementInTotoV01,
+			PredicateType: "https://spdx.dev/Document",
+		},
+	})
+
+	a := ApplicationSnapshotImage{
+		attestations: []attestation.Attestation[any]{createSimpleAttestation(nil), sbom},
+	}
+
+	checks, err := a.AttestationSyntaxChecks(ctx)
+	require.NoError(t, err)
+	require.Len(t, checks, 2)
+	assert.Empty(t, checks[0].Errors)
+	assert.Equal(t, "https://spdx.dev/Document", checks[1].PredicateType)
+	assert.Equal(t, "https://spdx.dev/Document", checks[1].SchemaID)
+	assert.NotEmpty(t, checks[1].Errors)
+}
+
+func TestLoadAttestationSchema(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	ctx := utils.WithFS(context.Background(), fs)
+	require.NoError(t, afero.WriteFile(fs, "/invalid.json", []byte("not json"), 0o400))
+
+	_, err := LoadAttestationSchema(ctx, "/missing.json")
+	assert.ErrorContains(t, err, "EV004")
+
+	_, err = LoadAttestationSchema(ctx, "/invalid.json")
+	assert.ErrorContains(t, err, "EV004")
+
+	_, err = LoadAttestationSchema(ctx, "http://example.com/schema.json")
+	assert.ErrorContains(t, err, "insecure transport")
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/oci"
+
+	ecoci "github.com/enterprise-contract/ec-cli/internal/utils/oci"
 )
 
 type contextKey string
@@ -56,11 +58,11 @@ type defaultClient struct {
 }
 
 func (c *defaultClient) VerifyImageSignatures(ctx context.Context, ref name.Reference, opts *cosign.CheckOpts) ([]oci.Signature, bool, error) {
-	return cosign.VerifyImageSignatures(ctx, ref, opts)
+	return cosign.VerifyImageSignatures(ctx, ref, ecoci.WithContext(ctx, opts))
 }
 
 func (c *defaultClient) VerifyImageAttestations(ctx context.Context, ref name.Reference, opts *cosign.CheckOpts) ([]oci.Signature, bool, error) {
-	return cosign.VerifyImageAttestations(ctx, ref, opts)
+	return cosign.VerifyImageAttestations(ctx, ref, ecoci.WithContext(ctx, opts))
 }
 
 func (c *defaultClient) Head(ref name.Reference, opts ...remote.Option) (*gcr.Descriptor, error) {
@@ -0,0 +1,242 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package application_snapshot_image
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	gcr "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/layout"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+// LocalImagePrefix is the prefix of image references pointing to a local OCI
+// layout, either a directory or a (gzipped) tarball, as written by
+// `cosign save`, containing the image with its signatures and attestations.
+const LocalImagePrefix = "oci:"
+
+// refNameAnnotation is the standard annotation holding the reference name of
+// an image within an OCI layout
+const refNameAnnotation = "org.opencontainers.image.ref.name"
+
+var invalidRepositoryChars = regexp.MustCompile(`[^a-z0-9._/-]+`)
+
+// IsLocalImage returns true if the url points to a local OCI layout.
+func IsLocalImage(url string) bool {
+	return strings.HasPrefix(url, LocalImagePrefix)
+}
+
+// LocalImage is an image, with its signatures and attestations, read from a
+// local OCI layout.
+type LocalImage struct {
+	// Path of the OCI layout directory
+	Path string
+	// Reference of the image, including the digest, either the one recorded
+	// in the OCI layout or derived from its file name
+	Reference name.Digest
+	fs        afero.Fs
+	tempDir   string
+}
+
+// OpenLocalImage opens the OCI layout at the location given by the url, the
+// url must have the LocalImagePrefix. Tarballs are extracted to a temporary
+// directory, invoke Close to remove it. Note that cosign reads the OCI layout
+// itself directly from the disk.
+func OpenLocalImage(ctx context.Context, url string) (*LocalImage, error) {
+	location := strings.TrimPrefix(url, LocalImagePrefix)
+
+	fs := utils.FS(ctx)
+	info, err := fs.Stat(location)
+	if err != nil {
+		return nil, err
+	}
+
+	l := LocalImage{Path: location, fs: fs}
+	if !info.IsDir() {
+		if l.tempDir, err = afero.TempDir(fs, afero.GetTempDir(fs, ""), "ec-oci-layout-"); err != nil {
+			return nil, err
+		}
+		if err := extract(fs, location, l.tempDir); err != nil {
+			l.Close()
+			return nil, err
+		}
+		l.Path = l.tempDir
+	}
+
+	desc, err := localDescriptor(l.Path)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+
+	if l.Reference, err = localReference(location, desc); err != nil {
+		l.Close()
+		return nil, err
+	}
+
+	log.Debugf("Opened local image %s from %s", l.Reference, location)
+
+	return &l, nil
+}
+
+// Close removes any temporary files created when opening the LocalImage.
+func (l *LocalImage) Close() {
+	if l.tempDir == "" {
+		return
+	}
+
+	if err := l.fs.RemoveAll(l.tempDir); err != nil {
+		log.Debugf("Ignoring error removing temporary directory %s: %v", l.tempDir, err)
+	}
+}
+
+// Client returns a Client that reads the image, signatures and attestations
+// from the LocalImage instead of from the registry.
+func (l *LocalImage) Client() Client {
+	return &localClient{path: l.Path}
+}
+
+type localClient struct {
+	path string
+}
+
+func (c *localClient) VerifyImageSignatures(ctx context.Context, _ name.Reference, opts *cosign.CheckOpts) ([]oci.Signature, bool, error) {
+	return cosign.VerifyLocalImageSignatures(ctx, c.path, opts)
+}
+
+func (c *localClient) VerifyImageAttestations(ctx context.Context, _ name.Reference, opts *cosign.CheckOpts) ([]oci.Signature, bool, error) {
+	return cosign.VerifyLocalImageAttestations(ctx, c.path, opts)
+}
+
+func (c *localClient) Head(_ name.Reference, _ ...remote.Option) (*gcr.Descriptor, error) {
+	return localDescriptor(c.path)
+}
+
+// localDescriptor returns the descriptor of the signed image, or image index,
+// within the OCI layout at the given path.
+func localDescriptor(path string) (*gcr.Descriptor, error) {
+	sii, err := layout.SignedImageIndex(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := sii.IndexManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range manifest.Manifests {
+		switch m.Annotations["kind"] {
+		case "dev.cosignproject.cosign/image", "dev.cosignproject.cosign/imageIndex":
+			desc := m
+			return &desc, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no image found in the OCI layout at %s", path)
+}
+
+// localReference determines the reference of the image in the OCI layout,
+// preferring the reference name recorded in the layout and falling back to a
+// reference in the localhost registry named after the layout location.
+func localReference(location string, desc *gcr.Descriptor) (name.Digest, error) {
+	// the reference name can also be just a tag, only consider it if it
+	// includes the repository
+	if refName := desc.Annotations[refNameAnnotation]; strings.Contains(refName, "/") {
+		if ref, err := name.ParseReference(refName); err == nil {
+			return ref.Context().Digest(desc.Digest.String()), nil
+		}
+	}
+
+	base := filepath.Base(location)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	base = strings.TrimSuffix(base, ".tar")
+	repository := strings.Trim(invalidRepositoryChars.ReplaceAllString(strings.ToLower(base), "-"), "-./")
+	if repository == "" {
+		repository = "image"
+	}
+
+	return name.NewDigest(fmt.Sprintf("localhost/%s@%s", repository, desc.Digest))
+}
+
+// extract extracts the (optionally gzipped) tarball into the dest directory
+func extract(fs afero.Fs, tarball, dest string) error {
+	f, err := fs.Open(tarball)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(tarball, ".gz") || strings.HasSuffix(tarball, ".tgz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, filepath.Clean("/"+hdr.Name))
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := fs.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := fs.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			out, err := fs.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, tr); err != nil { // #nosec G110 -- the tarball is provided by the user
+				out.Close()
+				return err
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package application_snapshot_image
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+// testImageLayout is an OCI layout, as written by `cosign save`, used by the
+// acceptance tests
+const testImageLayout = "../../../acceptance/image/testimage"
+
+const testImageDigest = "sha256:828dd7dda74384abf1b272ed55c2c70c804b875b7b426b228f0b2aa900a37d67"
+
+func TestIsLocalImage(t *testing.T) {
+	assert.True(t, IsLocalImage("oci:/path/to/layout"))
+	assert.False(t, IsLocalImage("registry.io/repository/image:tag"))
+}
+
+func TestOpenLocalImageDirectory(t *testing.T) {
+	local, err := OpenLocalImage(context.Background(), LocalImagePrefix+testImageLayout)
+	require.NoError(t, err)
+	defer local.Close()
+
+	assert.Equal(t, testImageLayout, local.Path)
+	assert.Equal(t, "localhost/testimage@"+testImageDigest, local.Reference.String())
+
+	desc, err := local.Client().Head(local.Reference)
+	require.NoError(t, err)
+	assert.Equal(t, testImageDigest, desc.Digest.String())
+}
+
+func TestOpenLocalImageTarball(t *testing.T) {
+	tarball := filepath.Join(t.TempDir(), "My Image.tar.gz")
+	writeTarball(t, testImageLayout, tarball)
+
+	local, err := OpenLocalImage(context.Background(), LocalImagePrefix+tarball)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, testImageLayout, local.Path)
+	assert.Equal(t, "localhost/my-image@"+testImageDigest, local.Reference.String())
+
+	desc, err := local.Client().Head(local.Reference)
+	require.NoError(t, err)
+	assert.Equal(t, testImageDigest, desc.Digest.String())
+
+	local.Close()
+	assert.NoDirExists(t, local.Path)
+}
+
+func TestOpenLocalImageMissing(t *testing.T) {
+	_, err := OpenLocalImage(context.Background(), LocalImagePrefix+filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
+
+func TestOpenLocalImageUsesFS(t *testing.T) {
+	ctx := utils.WithFS(context.Background(), afero.NewMemMapFs())
+
+	// exists on disk, but not in the in-memory file system
+	_, err := OpenLocalImage(ctx, LocalImagePrefix+testImageLayout)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestExtract(t *testing.T) {
+	tarball := filepath.Join(t.TempDir(), "image.tar.gz")
+	writeTarball(t, testImageLayout, tarball)
+
+	data, err := os.ReadFile(tarball)
+	require.NoError(t, err)
+
+	memfs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(memfs, "/image.tar.gz", data, 0o400))
+
+	require.NoError(t, extract(memfs, "/image.tar.gz", "/layout"))
+
+	exists, err := afero.Exists(memfs, "/layout/index.json")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func writeTarball(t *testing.T, dir, tarball string) {
+	f, err := os.Create(tarball)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	defer gz.Close()
+
+	tw := tar.NewWriter(gz)
+	defer tw.Close()
+
+	require.NoError(t, filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = rel
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	}))
+}
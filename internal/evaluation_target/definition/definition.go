@@ -19,36 +19,45 @@ package definition
 import (
 	"context"
 
+	ecc "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+
 	"github.com/enterprise-contract/ec-cli/internal/evaluator"
 	"github.com/enterprise-contract/ec-cli/internal/policy"
 	"github.com/enterprise-contract/ec-cli/internal/policy/source"
+	"github.com/enterprise-contract/ec-cli/internal/utils"
 )
 
 var newConftestEvaluator = evaluator.NewConftestEvaluatorWithNamespace
 
 // DefinitionFile represents the structure needed to evaluate a pipeline definition file
 type Definition struct {
-	Fpath     []string
-	Evaluator evaluator.Evaluator
+	Fpath      []string
+	Evaluators []evaluator.Evaluator
 }
 
-// NewPipelineDefinitionFile returns a DefinitionFile struct with FPath and evaluator ready to use
-func NewDefinition(ctx context.Context, fpath []string, sources []source.PolicySource, namespace []string) (*Definition, error) {
-	p := &Definition{
+// NewPipelineDefinitionFile returns a DefinitionFile struct with FPath and an
+// evaluator for each source group of the policy ready to use
+func NewDefinition(ctx context.Context, fpath []string, p policy.Policy, namespace []string) (*Definition, error) {
+	d := &Definition{
 		Fpath: fpath,
 	}
 
-	pol, err := policy.NewOfflinePolicy(ctx, policy.Now)
-	if err != nil {
-		return nil, err
-	}
-
-	c, err := newConftestEvaluator(ctx, sources, pol, namespace)
+	evaluators, err := utils.ParallelMap(ctx, p.Spec().Sources, func(ctx context.Context, sourceGroup ecc.Source) (evaluator.Evaluator, error) {
+		log.Debugf("Fetching policy source group '%s'", sourceGroup.Name)
+		policySources, err := source.FetchPolicySources(sourceGroup)
+		if err != nil {
+			return nil, err
+		}
 
+		return newConftestEvaluator(ctx, policySources, p, namespace)
+	})
 	if err != nil {
+		// release the work directories of the evaluators that were built
+		evaluator.DestroyAll(evaluators)
 		return nil, err
 	}
-	p.Evaluator = c
+	d.Evaluators = evaluators
 
-	return p, nil
+	return d, nil
 }
@@ -0,0 +1,71 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package input
+
+import (
+	"context"
+
+	ecc "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/enterprise-contract/ec-cli/internal/evaluator"
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+	"github.com/enterprise-contract/ec-cli/internal/policy/source"
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+var newConftestEvaluator = evaluator.NewConftestEvaluator
+
+// Input represents the structure needed to evaluate arbitrary JSON or YAML
+// documents against an EnterpriseContractPolicy
+type Input struct {
+	Paths      []string
+	Evaluators []evaluator.Evaluator
+}
+
+// NewInput returns an Input with an evaluator for each source group of the
+// policy, in the order of the source groups
+func NewInput(ctx context.Context, paths []string, p policy.Policy) (*Input, error) {
+	i := &Input{
+		Paths: paths,
+	}
+
+	evaluators, err := utils.ParallelMap(ctx, p.Spec().Sources, func(ctx context.Context, sourceGroup ecc.Source) (evaluator.Evaluator, error) {
+		log.Debugf("Fetching policy source group '%s'", sourceGroup.Name)
+		policySources, err := source.FetchPolicySources(sourceGroup)
+		if err != nil {
+			log.Debugf("Failed to fetch policy source group '%s'!", sourceGroup.Name)
+			return nil, err
+		}
+
+		c, err := newConftestEvaluator(ctx, policySources, p)
+		if err != nil {
+			log.Debug("Failed to initialize the conftest evaluator!")
+			return nil, err
+		}
+
+		return c, nil
+	})
+	if err != nil {
+		// release the work directories of the evaluators that were built
+		evaluator.DestroyAll(evaluators)
+		return nil, err
+	}
+	i.Evaluators = evaluators
+
+	return i, nil
+}
@@ -53,11 +53,39 @@ const (
 	runnerKey        contextKey = "ec.evaluator.runner"
 	capabilitiesKey  contextKey = "ec.evaluator.capabilities"
 	effectiveTimeKey contextKey = "ec.evaluator.effective_time"
+	componentKey     contextKey = "ec.evaluator.component"
+	imageRepoKey     contextKey = "ec.evaluator.image_repository"
 )
 
+// WithComponent records the name of the component being evaluated, used to
+// determine which policy exceptions apply.
+func WithComponent(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, componentKey, name)
+}
+
+// Component returns the name of the component being evaluated, as recorded
+// by WithComponent.
+func Component(ctx context.Context) string {
+	name, _ := ctx.Value(componentKey).(string)
+	return name
+}
+
+// WithImageRepository records the repository of the image being evaluated,
+// used to determine which policy exceptions apply.
+func WithImageRepository(ctx context.Context, repository string) context.Context {
+	return context.WithValue(ctx, imageRepoKey, repository)
+}
+
 type CheckResult struct {
 	output.CheckResult
 	Successes []output.Result `json:"successes,omitempty"`
+	// FutureViolations holds the failures of rules that are not yet
+	// effective, the effective_on metadata holds the date each becomes
+	// enforced
+	FutureViolations []output.Result `json:"futureViolations,omitempty"`
+	// Exempted holds the failures and warnings of rules covered by an
+	// active policy exception
+	Exempted []output.Result `json:"exempted,omitempty"`
 }
 
 type CheckResults []CheckResult
@@ -82,7 +110,7 @@ func (c *CheckResults) trim() {
 	reported := map[string]bool{}
 
 	for _, checks := range *c {
-		for _, results := range [][]output.Result{checks.Failures, checks.Warnings, checks.Skipped} {
+		for _, results := range [][]output.Result{checks.Failures, checks.Warnings, checks.Skipped, checks.FutureViolations, checks.Exempted} {
 			for _, result := range results {
 				if code, ok := result.Metadata[metadataCode].(string); ok {
 					reported[code] = true
@@ -124,6 +152,8 @@ func (c *CheckResults) trim() {
 		(*c)[i].Warnings = trimOutput(checks.Warnings)
 		(*c)[i].Skipped = trimOutput(checks.Skipped)
 		(*c)[i].Successes = trimOutput(checks.Successes)
+		(*c)[i].FutureViolations = trimOutput(checks.FutureViolations)
+		(*c)[i].Exempted = trimOutput(checks.Exempted)
 	}
 }
 
@@ -134,16 +164,18 @@ type testRunner interface {
 }
 
 const (
-	effectiveOnFormat   = "2006-01-02T15:04:05Z"
-	effectiveOnTimeout  = -90 * 24 * time.Hour // keep effective_on metadata up to 90 days
-	metadataCode        = "code"
-	metadataCollections = "collections"
-	metadataDependsOn   = "depends_on"
-	metadataDescription = "description"
-	metadataEffectiveOn = "effective_on"
-	metadataSolution    = "solution"
-	metadataTerm        = "term"
-	metadataTitle       = "title"
+	effectiveOnFormat        = "2006-01-02T15:04:05Z"
+	effectiveOnTimeout       = -90 * 24 * time.Hour // keep effective_on metadata up to 90 days
+	metadataCode             = "code"
+	metadataCollections      = "collections"
+	metadataDependsOn        = "depends_on"
+	metadataDescription      = "description"
+	metadataDocumentationUrl = "documentation_url"
+	metadataEffectiveOn      = "effective_on"
+	metadataException        = "exception"
+	metadataSolution         = "solution"
+	metadataTerm             = "term"
+	metadataTitle            = "title"
 )
 
 // ConftestEvaluator represents a structure which can be used to evaluate targets
@@ -287,6 +319,8 @@ func (c conftestEvaluator) Evaluate(ctx context.Context, inputs []string) (Check
 	// exist with the same code in two separate sources the collected rule
 	// information is not deterministic
 	rules := policyRules{}
+	policyDirs := []string{c.policyDir}
+	dataDirs := []string{c.dataDir}
 	// Download all sources
 	for _, s := range c.policySources {
 		dir, err := s.GetPolicy(ctx, c.workDir, false)
@@ -296,6 +330,17 @@ func (c conftestEvaluator) Evaluate(ctx context.Context, inputs []string) (Check
 			return nil, nil, err
 		}
 
+		// Sources shared via a cache are kept outside of the work directory
+		// and need to be provided to the runner explicitly
+		if source.IsShared(ctx, dir) {
+			switch s.Subdir() {
+			case "policy":
+				policyDirs = append(policyDirs, dir)
+			case "data":
+				dataDirs = append(dataDirs, dir)
+			}
+		}
+
 		fs := utils.FS(ctx)
 		annotations, err := opa.InspectDir(fs, dir)
 		if err != nil {
@@ -324,8 +369,8 @@ func (c conftestEvaluator) Evaluate(ctx context.Context, inputs []string) (Check
 
 		r = &conftestRunner{
 			runner.TestRunner{
-				Data:          []string{c.dataDir},
-				Policy:        []string{c.policyDir},
+				Data:          dataDirs,
+				Policy:        policyDirs,
 				Namespace:     c.namespace,
 				AllNamespaces: allNamespaces,
 				NoFail:        true,
@@ -347,12 +392,16 @@ func (c conftestEvaluator) Evaluate(ctx context.Context, inputs []string) (Check
 	effectiveTime := c.policy.EffectiveTime()
 	ctx = context.WithValue(ctx, effectiveTimeKey, effectiveTime)
 
+	exemptions := activeExceptions(ctx, c.policy.Exceptions(), effectiveTime)
+
 	// loop over each policy (namespace) evaluation
 	// effectively replacing the results returned from conftest
 	for i, result := range runResults {
 		log.Debugf("Evaluation result at %d: %#v", i, result)
 		warnings := []output.Result{}
 		failures := []output.Result{}
+		futureViolations := []output.Result{}
+		exempted := []output.Result{}
 		exceptions := []output.Result{}
 		skipped := []output.Result{}
 
@@ -364,6 +413,11 @@ func (c conftestEvaluator) Evaluate(ctx context.Context, inputs []string) (Check
 				log.Debugf("Skipping result warning: %#v", warning)
 				continue
 			}
+
+			if exemptions.exempt(&warning) {
+				exempted = append(exempted, warning)
+				continue
+			}
 			warnings = append(warnings, warning)
 		}
 
@@ -376,9 +430,13 @@ func (c conftestEvaluator) Evaluate(ctx context.Context, inputs []string) (Check
 				continue
 			}
 
+			if exemptions.exempt(&failure) {
+				exempted = append(exempted, failure)
+				continue
+			}
+
 			if !isResultEffective(failure, effectiveTime) {
-				// TODO: Instead of moving to warnings, create new attribute: "futureViolations"
-				warnings = append(warnings, failure)
+				futureViolations = append(futureViolations, failure)
 			} else {
 				failures = append(failures, failure)
 			}
@@ -402,6 +460,12 @@ func (c conftestEvaluator) Evaluate(ctx context.Context, inputs []string) (Check
 		result.Skipped = skipped
 
 		result := CheckResult{CheckResult: result}
+		if len(futureViolations) > 0 {
+			result.FutureViolations = futureViolations
+		}
+		if len(exempted) > 0 {
+			result.Exempted = exempted
+		}
 		result.Successes = c.computeSuccesses(result, rules, effectiveTime)
 
 		results = append(results, result)
@@ -421,6 +485,8 @@ func (c conftestEvaluator) Evaluate(ctx context.Context, inputs []string) (Check
 		total += res.CheckResult.Successes
 		total += len(res.Warnings)
 		total += len(res.Failures)
+		total += len(res.FutureViolations)
+		total += len(res.Exempted)
 	}
 	if total == 0 {
 		log.Error("no successes, warnings, or failures, check input")
@@ -437,7 +503,7 @@ func (c conftestEvaluator) computeSuccesses(result CheckResult, rules policyRule
 	// what rules, by code, have we seen in the Conftest results, use map to
 	// take advantage of hashing for quicker lookup
 	seenRules := map[string]bool{}
-	for _, o := range [][]output.Result{result.Failures, result.Warnings, result.Skipped, result.Exceptions} {
+	for _, o := range [][]output.Result{result.Failures, result.Warnings, result.Skipped, result.Exceptions, result.FutureViolations, result.Exempted} {
 		for _, r := range o {
 			if code, ok := r.Metadata[metadataCode].(string); ok {
 				seenRules[code] = true
@@ -559,6 +625,9 @@ func addMetadataToResults(ctx context.Context, r *output.Result, rule rule.Info)
 	if rule.Solution != "" {
 		r.Metadata[metadataSolution] = rule.Solution
 	}
+	if rule.DocumentationUrl != "" {
+		r.Metadata[metadataDocumentationUrl] = rule.DocumentationUrl
+	}
 	if len(rule.Collections) > 0 {
 		r.Metadata[metadataCollections] = rule.Collections
 	}
@@ -166,16 +166,18 @@ func TestConftestEvaluatorEvaluateTimeBased(t *testing.T) {
 							"effective_on": "2021-01-01T00:00:00Z",
 						},
 					},
-					{
-						Message: "not yet effective",
-						Metadata: map[string]any{
-							"effective_on": "3021-01-01T00:00:00Z",
-						},
-					},
 				},
 				Skipped:    []output.Result{},
 				Exceptions: []output.Result{},
 			},
+			FutureViolations: []output.Result{
+				{
+					Message: "not yet effective",
+					Metadata: map[string]any{
+						"effective_on": "3021-01-01T00:00:00Z",
+					},
+				},
+			},
 		},
 	}
 
@@ -30,3 +30,13 @@ type Evaluator interface {
 	// CapabilitiesPath returns the path to the file where capabilities are defined
 	CapabilitiesPath() string
 }
+
+// DestroyAll invokes Destroy on each of the given evaluators, skipping any
+// nil entries.
+func DestroyAll(evaluators []Evaluator) {
+	for _, e := range evaluators {
+		if e != nil {
+			e.Destroy()
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package evaluator
+
+import (
+	"context"
+	"time"
+
+	"github.com/open-policy-agent/conftest/output"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+)
+
+// exemptions holds the policy exceptions in effect for the component and image
+// being evaluated.
+type exemptions []policy.Exception
+
+// activeExceptions returns the exceptions that have not expired at the given
+// time and apply to the component and image repository recorded in the
+// context.
+func activeExceptions(ctx context.Context, exceptions []policy.Exception, at time.Time) exemptions {
+	component := Component(ctx)
+	imageRepository, _ := ctx.Value(imageRepoKey).(string)
+
+	active := exemptions{}
+	for _, e := range exceptions {
+		if !e.IsActive(at) {
+			log.Debugf("Ignoring expired exception for %q, expired on %s", e.Value, e.Expires)
+			continue
+		}
+		if !e.AppliesTo(component, imageRepository) {
+			continue
+		}
+		active = append(active, e)
+	}
+
+	return active
+}
+
+// exempt returns true if the result is covered by one of the exceptions, in
+// which case the details of the exception are added to the result's metadata.
+func (x exemptions) exempt(result *output.Result) bool {
+	if len(x) == 0 {
+		return false
+	}
+
+	matchers := makeMatchers(*result)
+	for _, e := range x {
+		if scoreMatches(matchers, []string{e.Value}) == 0 {
+			continue
+		}
+
+		if result.Metadata == nil {
+			result.Metadata = map[string]interface{}{}
+		}
+		result.Metadata[metadataException] = map[string]interface{}{
+			"value":         e.Value,
+			"justification": e.Justification,
+			"approver":      e.Approver,
+			"expires":       e.Expires,
+		}
+
+		return true
+	}
+
+	return false
+}
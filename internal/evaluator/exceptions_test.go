@@ -0,0 +1,86 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package evaluator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/open-policy-agent/conftest/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+)
+
+func TestExemptions(t *testing.T) {
+	p, err := policy.NewInertPolicy(context.Background(), heredoc.Doc(`
+		exceptions:
+		- value: pkg.rule
+		  expires: "2023-06-01"
+		  justification: waiting on upstream fix
+		  approver: security@example.com
+		- value: pkg.expired
+		  expires: "2023-01-01"
+		  justification: no longer needed
+		  approver: security@example.com
+		- value: pkg.other
+		  components: [api]
+		  expires: "2023-06-01"
+		  justification: not used by web
+		  approver: security@example.com
+		- value: "@special"
+		  imageRepositories: ["registry.io/org/*"]
+		  expires: "2023-06-01"
+		  justification: org-wide
+		  approver: security@example.com
+	`))
+	require.NoError(t, err)
+
+	ctx := WithImageRepository(WithComponent(context.Background(), "web"), "registry.io/org/web")
+	x := activeExceptions(ctx, p.Exceptions(), time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	result := func(code string, collections ...string) output.Result {
+		r := output.Result{Metadata: map[string]interface{}{metadataCode: code}}
+		if len(collections) > 0 {
+			r.Metadata[metadataCollections] = collections
+		}
+		return r
+	}
+
+	exempted := result("pkg.rule")
+	assert.True(t, x.exempt(&exempted))
+	assert.Equal(t, map[string]interface{}{
+		"value":         "pkg.rule",
+		"justification": "waiting on upstream fix",
+		"approver":      "security@example.com",
+		"expires":       "2023-06-01",
+	}, exempted.Metadata[metadataException])
+
+	inCollection := result("pkg.another", "special")
+	assert.True(t, x.exempt(&inCollection))
+
+	for _, code := range []string{"pkg.expired", "pkg.other", "pkg.unknown"} {
+		r := result(code)
+		assert.False(t, x.exempt(&r), code)
+		assert.NotContains(t, r.Metadata, metadataException)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"sort"
+
+	conftestOutput "github.com/open-policy-agent/conftest/output"
+
+	"github.com/enterprise-contract/ec-cli/internal/evaluator"
+)
+
+// FileResults holds the results of the evaluation of a single file, it is
+// embedded in the report items of commands validating files
+type FileResults struct {
+	Violations []conftestOutput.Result `json:"violations"`
+	Warnings   []conftestOutput.Result `json:"warnings"`
+	Successes  []conftestOutput.Result `json:"successes,omitempty"`
+	Success    bool                    `json:"success"`
+	// FutureViolations are violations of rules that are not yet effective
+	FutureViolations []conftestOutput.Result `json:"futureViolations,omitempty"`
+	// Exempted are violations and warnings covered by a policy exception
+	Exempted []conftestOutput.Result `json:"exempted,omitempty"`
+}
+
+// AddResultsByFile adds the results of the checks to the items holding the
+// results of the file each check pertains to, creating new items as needed,
+// and keeps the items sorted by file name. The file name and the results of
+// an item are accessed via the file function. Successes are added only if
+// showSuccesses is true. Returns the items and true if none of them has any
+// violations.
+func AddResultsByFile[T any](items []T, checks []evaluator.CheckResult, showSuccesses bool, file func(*T) (*string, *FileResults)) ([]T, bool) {
+	indexByFile := map[string]int{}
+	for i := range items {
+		name, _ := file(&items[i])
+		indexByFile[*name] = i
+	}
+
+	for _, check := range checks {
+		i, ok := indexByFile[check.FileName]
+		if !ok {
+			i = len(items)
+			indexByFile[check.FileName] = i
+
+			var item T
+			name, results := file(&item)
+			*name = check.FileName
+			results.Violations = []conftestOutput.Result{}
+			results.Warnings = []conftestOutput.Result{}
+			items = append(items, item)
+		}
+
+		_, results := file(&items[i])
+		results.Violations = append(results.Violations, check.Failures...)
+		results.Warnings = append(results.Warnings, check.Warnings...)
+		results.FutureViolations = append(results.FutureViolations, check.FutureViolations...)
+		results.Exempted = append(results.Exempted, check.Exempted...)
+		if showSuccesses {
+			results.Successes = append(results.Successes, check.Successes...)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		a, _ := file(&items[i])
+		b, _ := file(&items[j])
+		return *a < *b
+	})
+
+	success := true
+	for i := range items {
+		_, results := file(&items[i])
+		results.Success = len(results.Violations) == 0
+		if !results.Success {
+			success = false
+		}
+	}
+
+	return items, success
+}
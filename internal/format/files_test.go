@@ -0,0 +1,62 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"testing"
+
+	"github.com/open-policy-agent/conftest/output"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/enterprise-contract/ec-cli/internal/evaluator"
+)
+
+type testItem struct {
+	Name string
+	FileResults
+}
+
+func TestAddResultsByFile(t *testing.T) {
+	file := func(i *testItem) (*string, *FileResults) {
+		return &i.Name, &i.FileResults
+	}
+
+	pass := output.Result{Message: "Pass"}
+	violation := output.Result{Message: "Violation"}
+	warning := output.Result{Message: "Warning"}
+
+	items, success := AddResultsByFile(nil, []evaluator.CheckResult{
+		{CheckResult: output.CheckResult{FileName: "b.yaml", Successes: []output.Result{pass}}},
+		{CheckResult: output.CheckResult{FileName: "a.yaml", Warnings: []output.Result{warning}}},
+	}, false, file)
+
+	assert.True(t, success)
+	assert.Equal(t, []testItem{
+		{Name: "a.yaml", FileResults: FileResults{Violations: []output.Result{}, Warnings: []output.Result{warning}, Success: true}},
+		{Name: "b.yaml", FileResults: FileResults{Violations: []output.Result{}, Warnings: []output.Result{}, Success: true}},
+	}, items)
+
+	items, success = AddResultsByFile(items, []evaluator.CheckResult{
+		{CheckResult: output.CheckResult{FileName: "b.yaml", Failures: []output.Result{violation}, Successes: []output.Result{pass}}},
+	}, true, file)
+
+	assert.False(t, success)
+	assert.Equal(t, []testItem{
+		{Name: "a.yaml", FileResults: FileResults{Violations: []output.Result{}, Warnings: []output.Result{warning}, Success: true}},
+		{Name: "b.yaml", FileResults: FileResults{Violations: []output.Result{violation}, Warnings: []output.Result{}, Successes: []output.Result{pass}}},
+	}, items)
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jstemmer/go-junit-report/v2/junit"
+	conftestOutput "github.com/open-policy-agent/conftest/output"
+	"golang.org/x/exp/maps"
+)
+
+// AddJUnitTestcases adds a testcase to the suite for each of the results,
+// violations are reported as failures while warnings, future violations and
+// exempted violations are reported as skipped.
+func AddJUnitTestcases(suite *junit.Testsuite, successes, violations, warnings, futureViolations, exempted []conftestOutput.Result) {
+	mapResults(suite, successes, asTestCase)
+
+	mapResults(suite, violations, func(r conftestOutput.Result) junit.Testcase {
+		c := asTestCase(r)
+		c.Failure = &junit.Result{
+			Message: r.Message,
+			Data:    r.Message,
+		}
+
+		return c
+	})
+
+	mapResults(suite, warnings, func(r conftestOutput.Result) junit.Testcase {
+		c := asTestCase(r)
+		c.Skipped = &junit.Result{
+			Message: r.Message,
+			Data:    r.Message,
+		}
+
+		return c
+	})
+
+	mapResults(suite, futureViolations, func(r conftestOutput.Result) junit.Testcase {
+		c := asTestCase(r)
+		message := fmt.Sprintf("Future violation, enforced from %v: %s", r.Metadata["effective_on"], r.Message)
+		c.Skipped = &junit.Result{
+			Message: message,
+			Data:    message,
+		}
+
+		return c
+	})
+
+	mapResults(suite, exempted, func(r conftestOutput.Result) junit.Testcase {
+		c := asTestCase(r)
+		message := fmt.Sprintf("Exempted by policy exception: %s", r.Message)
+		if exception, ok := r.Metadata["exception"].(map[string]interface{}); ok {
+			message = fmt.Sprintf("Exempted by policy exception until %v, approved by %v (%v): %s", exception["expires"], exception["approver"], exception["justification"], r.Message)
+		}
+		c.Skipped = &junit.Result{
+			Message: message,
+			Data:    message,
+		}
+
+		return c
+	})
+}
+
+// mapResults maps an slice of Conftest results to a slice of arbitrary types
+// given a mapper function
+func mapResults(suite *junit.Testsuite, results []conftestOutput.Result, m func(conftestOutput.Result) junit.Testcase) {
+	for _, r := range results {
+		suite.AddTestcase(m(r))
+	}
+}
+
+func asTestCase(r conftestOutput.Result) junit.Testcase {
+	meta := maps.Clone(r.Metadata)
+	delete(meta, "code")
+
+	metaDesc := make([]string, 0, 3)
+	for k, v := range meta {
+		metaDesc = append(metaDesc, fmt.Sprintf("%s=%v", k, v))
+	}
+
+	desc := ""
+	if len(metaDesc) > 0 {
+		sort.Strings(metaDesc)
+		desc = " [" + strings.Join(metaDesc, ", ") + "]"
+	}
+
+	name := fmt.Sprintf("%s%s", r.Message, desc)
+
+	if code, ok := r.Metadata["code"].(string); ok {
+		name = fmt.Sprintf("%s: %s", code, name)
+	}
+
+	return junit.Testcase{
+		Name:      name,
+		Classname: name, // some reporting tools might require Classname as well
+	}
+}
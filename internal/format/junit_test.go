@@ -0,0 +1,105 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"testing"
+
+	"github.com/jstemmer/go-junit-report/v2/junit"
+	"github.com/open-policy-agent/conftest/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapResults(t *testing.T) {
+	s := junit.Testsuite{}
+	mapResults(&s, []output.Result{{Message: "0"}, {Message: "1"}, {Message: "2"}}, func(r output.Result) junit.Testcase {
+		return junit.Testcase{
+			Name: r.Message,
+		}
+	})
+
+	assert.Equal(t, junit.Testsuite{
+		Tests: 3,
+		Testcases: []junit.Testcase{
+			{Name: "0"},
+			{Name: "1"},
+			{Name: "2"},
+		},
+	}, s)
+
+}
+
+func TestAsTestCase(t *testing.T) {
+	cases := []struct {
+		name     string
+		result   output.Result
+		expected junit.Testcase
+	}{
+		{
+			name:     "nil",
+			expected: junit.Testcase{},
+		},
+		{
+			name:     "trivial",
+			result:   output.Result{Message: "msg"},
+			expected: junit.Testcase{Name: "msg", Classname: "msg"},
+		},
+		{
+			name:     "with code",
+			result:   output.Result{Message: "msg", Metadata: map[string]interface{}{"code": "a.b.c"}},
+			expected: junit.Testcase{Name: "a.b.c: msg", Classname: "a.b.c: msg"},
+		},
+		{
+			name:     "with metadata",
+			result:   output.Result{Message: "msg", Metadata: map[string]interface{}{"x": "1", "y": "2", "z": "3"}},
+			expected: junit.Testcase{Name: "msg [x=1, y=2, z=3]", Classname: "msg [x=1, y=2, z=3]"},
+		},
+		{
+			name:     "with code and metadata",
+			result:   output.Result{Message: "msg", Metadata: map[string]interface{}{"code": "a.b.c", "x": "1", "y": "2", "z": "3"}},
+			expected: junit.Testcase{Name: "a.b.c: msg [x=1, y=2, z=3]", Classname: "a.b.c: msg [x=1, y=2, z=3]"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := asTestCase(c.result)
+
+			assert.Equal(t, c.expected, got)
+		})
+	}
+}
+
+func TestAddJUnitTestcases(t *testing.T) {
+	s := junit.Testsuite{}
+	AddJUnitTestcases(&s,
+		[]output.Result{{Message: "success"}},
+		[]output.Result{{Message: "violation"}},
+		[]output.Result{{Message: "warning"}},
+		[]output.Result{{Message: "future", Metadata: map[string]interface{}{"effective_on": "2099-01-01T00:00:00Z"}}},
+		[]output.Result{{Message: "exempted"}},
+	)
+
+	assert.Equal(t, 5, s.Tests)
+	assert.Equal(t, 1, s.Failures)
+	assert.Equal(t, 3, s.Skipped)
+	assert.Nil(t, s.Testcases[0].Failure)
+	assert.Equal(t, "violation", s.Testcases[1].Failure.Message)
+	assert.Equal(t, "warning", s.Testcases[2].Skipped.Message)
+	assert.Equal(t, "Future violation, enforced from 2099-01-01T00:00:00Z: future", s.Testcases[3].Skipped.Message)
+	assert.Equal(t, "Exempted by policy exception: exempted", s.Testcases[4].Skipped.Message)
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"fmt"
+
+	conftestOutput "github.com/open-policy-agent/conftest/output"
+)
+
+// CondensedMessages reduces repetitive messages, only the first message of
+// each rule, identified by its code, is kept followed by the number of the
+// remaining ones.
+func CondensedMessages(results []conftestOutput.Result) map[string][]string {
+	maxErr := 1
+	shortNames := make(map[string][]string)
+	count := make(map[string]int)
+	for _, v := range results {
+		code, isPresent := v.Metadata["code"]
+		// we don't want to keep count of the empty string
+		if isPresent {
+			code := fmt.Sprintf("%v", code)
+			if count[code] < maxErr {
+				shortNames[code] = append(shortNames[code], v.Message)
+			}
+			count[code] = count[code] + 1
+		}
+	}
+	for k := range shortNames {
+		if count[k] > maxErr {
+			shortNames[k] = append(shortNames[k], fmt.Sprintf("There are %v more %q messages", count[k]-1, k))
+		}
+	}
+	return shortNames
+}
+
+// WithEnforcementDate returns a copy of the results with the date each becomes
+// enforced, from the effective_on metadata, appended to the message.
+func WithEnforcementDate(results []conftestOutput.Result) []conftestOutput.Result {
+	dated := make([]conftestOutput.Result, 0, len(results))
+	for _, r := range results {
+		if effectiveOn, ok := r.Metadata["effective_on"].(string); ok && effectiveOn != "" {
+			r.Message = fmt.Sprintf("%s (enforced from %s)", r.Message, effectiveOn)
+		}
+		dated = append(dated, r)
+	}
+
+	return dated
+}
+
+// WithExceptionExpiry returns a copy of the results with the date the policy
+// exception covering each expires, from the exception metadata, appended to the
+// message.
+func WithExceptionExpiry(results []conftestOutput.Result) []conftestOutput.Result {
+	expiring := make([]conftestOutput.Result, 0, len(results))
+	for _, r := range results {
+		if exception, ok := r.Metadata["exception"].(map[string]interface{}); ok {
+			if expires, ok := exception["expires"].(string); ok && expires != "" {
+				r.Message = fmt.Sprintf("%s (exempted until %s)", r.Message, expires)
+			}
+		}
+		expiring = append(expiring, r)
+	}
+
+	return expiring
+}
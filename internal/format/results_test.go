@@ -0,0 +1,62 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"testing"
+
+	"github.com/open-policy-agent/conftest/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCondensedMessages(t *testing.T) {
+	results := []output.Result{
+		{Message: "first", Metadata: map[string]interface{}{"code": "a.b"}},
+		{Message: "second", Metadata: map[string]interface{}{"code": "a.b"}},
+		{Message: "third", Metadata: map[string]interface{}{"code": "a.b"}},
+		{Message: "other", Metadata: map[string]interface{}{"code": "c.d"}},
+		{Message: "no code"},
+	}
+
+	assert.Equal(t, map[string][]string{
+		"a.b": {"first", `There are 2 more "a.b" messages`},
+		"c.d": {"other"},
+	}, CondensedMessages(results))
+}
+
+func TestWithEnforcementDate(t *testing.T) {
+	results := []output.Result{
+		{Message: "dated", Metadata: map[string]interface{}{"effective_on": "2099-01-01T00:00:00Z"}},
+		{Message: "undated"},
+	}
+
+	dated := WithEnforcementDate(results)
+	assert.Equal(t, "dated (enforced from 2099-01-01T00:00:00Z)", dated[0].Message)
+	assert.Equal(t, "undated", dated[1].Message)
+	assert.Equal(t, "dated", results[0].Message)
+}
+
+func TestWithExceptionExpiry(t *testing.T) {
+	results := []output.Result{
+		{Message: "expiring", Metadata: map[string]interface{}{"exception": map[string]interface{}{"expires": "2099-01-01"}}},
+		{Message: "forever", Metadata: map[string]interface{}{"exception": map[string]interface{}{}}},
+	}
+
+	expiring := WithExceptionExpiry(results)
+	assert.Equal(t, "expiring (exempted until 2099-01-01)", expiring[0].Message)
+	assert.Equal(t, "forever", expiring[1].Message)
+}
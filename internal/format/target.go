@@ -21,6 +21,8 @@ import (
 	"strings"
 
 	"github.com/spf13/afero"
+
+	"github.com/enterprise-contract/ec-cli/internal/utils"
 )
 
 // Target represents a writer with a specified format.
@@ -34,6 +36,12 @@ func (t *Target) Write(data []byte) (int, error) {
 	return t.writer.Write(data)
 }
 
+// IsTerminal returns true if the target writes to a terminal, i.e. it is
+// safe to color the output.
+func (t *Target) IsTerminal() bool {
+	return utils.IsTerminal(t.writer)
+}
+
 // TargetParser is responsible for creating Target objects.
 type TargetParser struct {
 	defaultFormat string
@@ -46,6 +54,11 @@ func NewTargetParser(targetName string, writer io.Writer, fs afero.Fs) TargetPar
 	return TargetParser{defaultFormat: targetName, defaultWriter: writer, fs: fs}
 }
 
+// FS returns the file system the targets are written to.
+func (tm *TargetParser) FS() afero.Fs {
+	return tm.fs
+}
+
 // Parse creates a new Target given the provided target name.
 func (tm *TargetParser) Parse(name string) Target {
 	target := Target{writer: tm.defaultWriter}
@@ -0,0 +1,96 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	conftestOutput "github.com/open-policy-agent/conftest/output"
+)
+
+const (
+	ansiReset  = "\033[0m"
+	ansiBold   = "\033[1m"
+	ansiRed    = "\033[31m"
+	ansiGreen  = "\033[32m"
+	ansiYellow = "\033[33m"
+	ansiFaint  = "\033[2m"
+)
+
+// Painter colors text using ANSI escape sequences, when disabled the text is
+// returned as is.
+type Painter struct {
+	enabled bool
+}
+
+// NewPainter returns a Painter that colors text if enabled is true, unless
+// the NO_COLOR environment variable is set (https://no-color.org).
+func NewPainter(enabled bool) Painter {
+	return Painter{enabled: enabled && os.Getenv("NO_COLOR") == ""}
+}
+
+func (p Painter) paint(code, s string) string {
+	if !p.enabled {
+		return s
+	}
+
+	return code + s + ansiReset
+}
+
+func (p Painter) Bold(s string) string   { return p.paint(ansiBold, s) }
+func (p Painter) Red(s string) string    { return p.paint(ansiRed, s) }
+func (p Painter) Green(s string) string  { return p.paint(ansiGreen, s) }
+func (p Painter) Yellow(s string) string { return p.paint(ansiYellow, s) }
+func (p Painter) Faint(s string) string  { return p.paint(ansiFaint, s) }
+
+// Status returns the colored status given the number of violations and
+// warnings: FAIL, WARN or PASS.
+func (p Painter) Status(success bool, warnings int) string {
+	switch {
+	case !success:
+		return p.Red("FAIL")
+	case warnings > 0:
+		return p.Yellow("WARN")
+	default:
+		return p.Green("PASS")
+	}
+}
+
+// WriteTextResults writes each of the results with its code and message,
+// and the title and solution when present in the metadata, i.e. when the
+// detailed information was requested. The label, e.g. "Violation", is colored
+// with the given color function.
+func WriteTextResults(w io.Writer, label string, color func(string) string, results []conftestOutput.Result) {
+	for _, r := range results {
+		code, _ := r.Metadata["code"].(string)
+		if code == "" {
+			fmt.Fprintf(w, "  %s\n", color(label))
+		} else {
+			fmt.Fprintf(w, "  %s %s\n", color(label), code)
+		}
+		fmt.Fprintf(w, "    %s\n", r.Message)
+
+		if title, ok := r.Metadata["title"].(string); ok && title != "" {
+			fmt.Fprintf(w, "    Title: %s\n", title)
+		}
+		if solution, ok := r.Metadata["solution"].(string); ok && solution != "" {
+			fmt.Fprintf(w, "    Solution: %s\n", solution)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package format
+
+import (
+	"bytes"
+	"testing"
+
+	conftestOutput "github.com/open-policy-agent/conftest/output"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPainter(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	assert.Equal(t, "\033[31mtext\033[0m", NewPainter(true).Red("text"))
+	assert.Equal(t, "text", NewPainter(false).Red("text"))
+
+	t.Setenv("NO_COLOR", "1")
+	assert.Equal(t, "text", NewPainter(true).Red("text"))
+}
+
+func TestPainterStatus(t *testing.T) {
+	p := NewPainter(false)
+
+	assert.Equal(t, "FAIL", p.Status(false, 1))
+	assert.Equal(t, "WARN", p.Status(true, 1))
+	assert.Equal(t, "PASS", p.Status(true, 0))
+}
+
+func TestWriteTextResults(t *testing.T) {
+	buf := bytes.Buffer{}
+	WriteTextResults(&buf, "Violation", NewPainter(false).Red, []conftestOutput.Result{
+		{
+			Message: "Missing required task",
+			Metadata: map[string]interface{}{
+				"code":     "tasks.required",
+				"title":    "Required tasks",
+				"solution": "Add the task to the pipeline",
+			},
+		},
+		{Message: "No code"},
+	})
+
+	assert.Equal(t, "  Violation tasks.required\n"+
+		"    Missing required task\n"+
+		"    Title: Required tasks\n"+
+		"    Solution: Add the task to the pipeline\n"+
+		"  Violation\n"+
+		"    No code\n", buf.String())
+}
@@ -36,6 +36,10 @@ func (f fakeAtt) Data() []byte {
 	return bytes
 }
 
+func (f fakeAtt) PredicateType() string {
+	return f.statement.PredicateType
+}
+
 func (f fakeAtt) Statement() in_toto.ProvenanceStatementSLSA02 {
 	return f.statement
 }
@@ -43,3 +47,27 @@ func (f fakeAtt) Statement() in_toto.ProvenanceStatementSLSA02 {
 func (f fakeAtt) Signatures() []output.EntitySignature {
 	return nil
 }
+
+type fakeAttV1 struct {
+	statement in_toto.ProvenanceStatementSLSA1
+}
+
+func (f fakeAttV1) Data() []byte {
+	bytes, err := json.Marshal(f.statement)
+	if err != nil {
+		panic(err)
+	}
+	return bytes
+}
+
+func (f fakeAttV1) PredicateType() string {
+	return f.statement.PredicateType
+}
+
+func (f fakeAttV1) Statement() in_toto.ProvenanceStatementSLSA1 {
+	return f.statement
+}
+
+func (f fakeAttV1) Signatures() []output.EntitySignature {
+	return nil
+}
@@ -22,7 +22,6 @@ import (
 	"sort"
 	"time"
 
-	"github.com/in-toto/in-toto-golang/in_toto"
 	conftestOutput "github.com/open-policy-agent/conftest/output"
 	"github.com/qri-io/jsonpointer"
 	log "github.com/sirupsen/logrus"
@@ -32,6 +31,8 @@ import (
 	"github.com/enterprise-contract/ec-cli/internal/evaluator"
 	"github.com/enterprise-contract/ec-cli/internal/output"
 	"github.com/enterprise-contract/ec-cli/internal/policy"
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+	"github.com/enterprise-contract/ec-cli/internal/vsa"
 )
 
 // ValidateImage executes the required method calls to evaluate a given policy
@@ -39,8 +40,38 @@ import (
 func ValidateImage(ctx context.Context, url string, p policy.Policy, detailed bool) (*output.Output, error) {
 	log.Debugf("Validating image %s", url)
 
+	if application_snapshot_image.IsLocalImage(url) {
+		local, err := application_snapshot_image.OpenLocalImage(ctx, url)
+		if err != nil {
+			log.Debugf("Failed to open local image %s", url)
+			return nil, err
+		}
+		defer local.Close()
+
+		// From here on the image, signatures and attestations are read from
+		// the local OCI layout
+		ctx = application_snapshot_image.WithClient(ctx, local.Client())
+		url = local.Reference.String()
+	}
+
+	// Verification Summary Attestations identify the policy as resolved for
+	// the component from the policy as given
+	unresolved := p
+
+	// Policy exceptions and overrides can be limited to images from specific
+	// repositories
+	var repository string
+	if ref, err := NewImageReference(url); err == nil {
+		repository = ref.Repository
+		ctx = evaluator.WithImageRepository(ctx, repository)
+
+		if p, err = p.ForComponent(evaluator.Component(ctx), repository); err != nil {
+			return nil, err
+		}
+	}
+
 	out := &output.Output{ImageURL: url, Detailed: detailed, Policy: p}
-	a, err := application_snapshot_image.NewApplicationSnapshotImage(ctx, url, p)
+	a, err := application_snapshot_image.NewImage(url, p)
 	if err != nil {
 		log.Debug("Failed to create application snapshot image!")
 		return nil, err
@@ -57,6 +88,21 @@ func ValidateImage(ctx context.Context, url string, p policy.Policy, detailed bo
 		out.ImageURL = resolved
 	}
 
+	// The Verification Summary Attestation is looked up before the policy
+	// sources are fetched, if one is found the evaluation is skipped entirely
+	if checker := vsa.CheckerFrom(ctx); checker != nil {
+		policyDigest, err := vsa.PolicyDigest(unresolved, evaluator.Component(ctx), repository)
+		if err != nil {
+			return nil, err
+		}
+
+		if timeVerified, found := a.VerificationSummary(ctx, checker, policyDigest); found {
+			log.Debugf("Image %s verified by a Verification Summary Attestation, skipping the evaluation", out.ImageURL)
+			out.SetVerificationSummaryCheck(timeVerified)
+			return out, nil
+		}
+	}
+
 	out.SetImageSignatureCheckFromError(a.ValidateImageSignature(ctx))
 
 	out.SetAttestationSignatureCheckFromError(a.ValidateAttestationSignature(ctx))
@@ -66,15 +112,18 @@ func ValidateImage(ctx context.Context, url string, p policy.Policy, detailed bo
 
 	out.Signatures = a.Signatures()
 
-	out.SetAttestationSyntaxCheckFromError(a.ValidateAttestationSyntax(ctx))
+	out.SetAttestationSyntaxChecks(a.AttestationSyntaxChecks(ctx))
 
 	if attestationTime := determineAttestationTime(ctx, a.Attestations()); attestationTime != nil {
 		p.AttestationTime(*attestationTime)
 	}
 
+	// Attestations of other predicate types, e.g. SBOMs, are provided to the
+	// policy, but at least one SLSA Provenance is required
 	attCount := len(a.Attestations())
-	log.Debugf("Found %d attestations", attCount)
-	if attCount == 0 {
+	provenanceCount := len(a.ProvenanceAttestations())
+	log.Debugf("Found %d attestations, %d of which are SLSA Provenance", attCount, provenanceCount)
+	if provenanceCount == 0 {
 		// This is very much a corner case.
 		out.SetPolicyCheck(evaluator.CheckResults{
 			{
@@ -89,24 +138,44 @@ func ValidateImage(ctx context.Context, url string, p policy.Policy, detailed bo
 		return out, nil
 	}
 
+	if err := a.LoadEvaluators(ctx, p); err != nil {
+		log.Debug("Failed to load the policy evaluators!")
+		return nil, err
+	}
+	for _, e := range a.Evaluators {
+		defer e.Destroy()
+	}
+
 	input, err := a.WriteInputFile(ctx)
 	if err != nil {
 		log.Debug("Problem writing input files!")
 		return nil, err
 	}
 
-	var allResults evaluator.CheckResults
-	for _, e := range a.Evaluators {
-		// Todo maybe: Handle each one concurrently
-		results, data, err := e.Evaluate(ctx, []string{input})
-		defer e.Destroy()
+	type evaluation struct {
+		results evaluator.CheckResults
+		data    evaluator.Data
+	}
 
+	// Evaluators are run concurrently, the results are collected in the order
+	// of the evaluators to keep the report deterministic
+	evaluations, err := utils.ParallelMap(ctx, a.Evaluators, func(ctx context.Context, e evaluator.Evaluator) (evaluation, error) {
+		results, data, err := e.Evaluate(ctx, []string{input})
 		if err != nil {
 			log.Debug("Problem running conftest policy check!")
-			return nil, err
+			return evaluation{}, err
 		}
-		allResults = append(allResults, results...)
-		out.Data = append(out.Data, data)
+
+		return evaluation{results, data}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var allResults evaluator.CheckResults
+	for _, e := range evaluations {
+		allResults = append(allResults, e.results...)
+		out.Data = append(out.Data, e.data)
 	}
 
 	log.Debug("Conftest policy check complete")
@@ -141,16 +210,27 @@ func resolveAndSetImageUrl(url string, asi *application_snapshot_image.Applicati
 	return resolved, nil
 }
 
-func determineAttestationTime(ctx context.Context, attestations []attestation.Attestation[in_toto.ProvenanceStatementSLSA02]) *time.Time {
+// buildFinishedOnPointers hold the locations of the build finish time within
+// SLSA Provenance v0.2 and v1.0 statements respectively
+var buildFinishedOnPointers = []string{
+	"/predicate/metadata/buildFinishedOn",
+	"/predicate/runDetails/metadata/finishedOn",
+}
+
+func determineAttestationTime[T any](ctx context.Context, attestations []attestation.Attestation[T]) *time.Time {
 	if len(attestations) == 0 {
 		log.Debug("No attestations provided to determine attestation time")
 		return nil
 	}
 
-	pointer, err := jsonpointer.Parse("/predicate/metadata/buildFinishedOn")
-	if err != nil {
-		log.Debugf("Failed to parse the fixed JSON Pointer: %v", err)
-		panic(err)
+	pointers := make([]jsonpointer.Pointer, 0, len(buildFinishedOnPointers))
+	for _, p := range buildFinishedOnPointers {
+		pointer, err := jsonpointer.Parse(p)
+		if err != nil {
+			log.Debugf("Failed to parse the fixed JSON Pointer: %v", err)
+			panic(err)
+		}
+		pointers = append(pointers, pointer)
 	}
 
 	times := make([]time.Time, 0, len(attestations))
@@ -160,9 +240,15 @@ func determineAttestationTime(ctx context.Context, attestations []attestation.At
 		if err := json.Unmarshal(data, &obj); err != nil {
 			continue
 		}
-		maybeFinishTime, err := pointer.Eval(obj)
-		if err != nil {
+		var maybeFinishTime any
+		for _, pointer := range pointers {
+			if v, err := pointer.Eval(obj); err == nil && v != nil {
+				maybeFinishTime = v
+				break
+			}
 			log.Debugf("Failed to evaluate JSON Pointer %s for attestation at %d", pointer, i)
+		}
+		if maybeFinishTime == nil {
 			continue
 		}
 
@@ -31,6 +31,7 @@ import (
 	"github.com/in-toto/in-toto-golang/in_toto"
 	"github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/common"
 	v02 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v0.2"
+	slsa1 "github.com/in-toto/in-toto-golang/in_toto/slsa_provenance/v1"
 	conftestOutput "github.com/open-policy-agent/conftest/output"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	"github.com/sigstore/cosign/v2/pkg/oci"
@@ -196,6 +197,34 @@ func TestDetermineAttestationTime(t *testing.T) {
 	}
 }
 
+func TestDetermineAttestationTimeV1(t *testing.T) {
+	time1 := time.Date(2001, 2, 3, 4, 5, 6, 7, time.UTC)
+	att1 := fakeAttV1{
+		statement: in_toto.ProvenanceStatementSLSA1{
+			StatementHeader: in_toto.StatementHeader{
+				PredicateType: slsa1.PredicateSLSAProvenance,
+			},
+			Predicate: slsa1.ProvenancePredicate{
+				RunDetails: slsa1.ProvenanceRunDetails{
+					BuildMetadata: slsa1.BuildMetadata{
+						FinishedOn: &time1,
+					},
+				},
+			},
+		},
+	}
+	att2 := fakeAttV1{
+		statement: in_toto.ProvenanceStatementSLSA1{
+			StatementHeader: in_toto.StatementHeader{
+				PredicateType: slsa1.PredicateSLSAProvenance,
+			},
+		},
+	}
+
+	assert.Equal(t, &time1, determineAttestationTime(context.TODO(), []attestation.Attestation[in_toto.ProvenanceStatementSLSA1]{att1, att2}))
+	assert.Nil(t, determineAttestationTime(context.TODO(), []attestation.Attestation[in_toto.ProvenanceStatementSLSA1]{att2}))
+}
+
 type mockASIClient struct {
 	head         *gcr.Descriptor
 	signatures   []oci.Signature
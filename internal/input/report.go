@@ -0,0 +1,164 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	ecc "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/enterprise-contract/ec-cli/internal/format"
+	"github.com/enterprise-contract/ec-cli/internal/output"
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+	"github.com/enterprise-contract/ec-cli/internal/sarif"
+	"github.com/enterprise-contract/ec-cli/internal/version"
+)
+
+type Input struct {
+	FilePath string `json:"filepath"`
+	format.FileResults
+}
+
+const (
+	JSON  string = "json"
+	YAML  string = "yaml"
+	SARIF string = "sarif"
+	TEXT  string = "text"
+)
+
+type Report struct {
+	Success       bool                             `json:"success"`
+	FilePaths     []Input                          `json:"filepaths"`
+	Policy        ecc.EnterpriseContractPolicySpec `json:"policy"`
+	EcVersion     string                           `json:"ec-version"`
+	EffectiveTime time.Time                        `json:"effective-time"`
+	showSuccesses bool
+	// rules holds the metadata of the rules of the reported results
+	rules map[string]map[string]interface{}
+}
+
+// NewReport returns an empty report of the validation against the policy,
+// successes are included if showSuccesses is true
+func NewReport(p policy.Policy, showSuccesses bool) Report {
+	info, _ := version.ComputeInfo()
+	return Report{
+		Success:       true,
+		FilePaths:     []Input{},
+		Policy:        p.Spec(),
+		EcVersion:     info.Version,
+		EffectiveTime: p.EffectiveTime().UTC(),
+		showSuccesses: showSuccesses,
+	}
+}
+
+// Add records the results of the output grouped by the input file they
+// pertain to, the inputs are kept sorted by their path.
+func (r *Report) Add(o output.Output) {
+	if r.rules == nil {
+		r.rules = map[string]map[string]interface{}{}
+	}
+	for code, metadata := range o.Rules {
+		r.rules[code] = metadata
+	}
+
+	r.FilePaths, r.Success = format.AddResultsByFile(r.FilePaths, o.PolicyCheck, r.showSuccesses, func(i *Input) (*string, *format.FileResults) {
+		return &i.FilePath, &i.FileResults
+	})
+}
+
+// toSARIF returns a version of the report in the SARIF format, with the input
+// files as the artifacts
+func (r *Report) toSARIF() sarif.Log {
+	b := sarif.NewBuilder(r.EcVersion)
+	b.AddRuleMetadata(r.rules)
+	for _, i := range r.FilePaths {
+		b.Add(i.FilePath, "", sarif.LevelError, i.Violations)
+		b.Add(i.FilePath, "", sarif.LevelWarning, i.Warnings)
+		b.Add(i.FilePath, "", sarif.LevelNote, i.FutureViolations)
+		b.Add(i.FilePath, "", sarif.LevelNote, i.Exempted)
+	}
+
+	return b.Log()
+}
+
+// toText returns a human readable version of the report, grouped by input
+// file, colored if color is true
+func (r *Report) toText(color bool) []byte {
+	p := format.NewPainter(color)
+	buf := bytes.Buffer{}
+
+	var violations, warnings int
+	for _, i := range r.FilePaths {
+		violations += len(i.Violations)
+		warnings += len(i.Warnings)
+	}
+
+	fmt.Fprintf(&buf, "%s %s\n", p.Bold("Success:"), p.Status(r.Success, warnings))
+	fmt.Fprintf(&buf, "Inputs: %d, Violations: %s, Warnings: %s\n",
+		len(r.FilePaths), p.Red(fmt.Sprint(violations)), p.Yellow(fmt.Sprint(warnings)))
+
+	for _, i := range r.FilePaths {
+		buf.WriteString("\n")
+		fmt.Fprintf(&buf, "%s %s\n", p.Bold("Input:"), i.FilePath)
+		fmt.Fprintf(&buf, "Result: %s (violations: %d, warnings: %d)\n",
+			p.Status(i.Success, len(i.Warnings)), len(i.Violations), len(i.Warnings))
+
+		format.WriteTextResults(&buf, "Violation", p.Red, i.Violations)
+		format.WriteTextResults(&buf, "Warning", p.Yellow, i.Warnings)
+		format.WriteTextResults(&buf, "Future violation", p.Yellow, i.FutureViolations)
+		format.WriteTextResults(&buf, "Exempted", p.Faint, i.Exempted)
+		format.WriteTextResults(&buf, "Success", p.Green, i.Successes)
+	}
+
+	return buf.Bytes()
+}
+
+// Write writes the report in the format, and to the destination, of the given
+// target, e.g. yaml=/tmp/report.yaml
+func (r *Report) Write(targetName string, p format.TargetParser) error {
+	var data []byte
+	var err error
+
+	target := p.Parse(targetName)
+
+	switch target.Format {
+	case JSON:
+		if data, err = json.Marshal(r); err != nil {
+			return err
+		}
+	case YAML:
+		if data, err = yaml.Marshal(r); err != nil {
+			return err
+		}
+	case SARIF:
+		if data, err = json.Marshal(r.toSARIF()); err != nil {
+			return err
+		}
+	case TEXT:
+		// colored only when written to a terminal
+		data = r.toText(target.IsTerminal())
+	default:
+		return fmt.Errorf("unexpected report format: %s", target.Format)
+	}
+
+	_, err = target.Write(data)
+	return err
+}
@@ -0,0 +1,125 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package input
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/MakeNowJust/heredoc"
+	conftest "github.com/open-policy-agent/conftest/output"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/evaluator"
+	"github.com/enterprise-contract/ec-cli/internal/format"
+	"github.com/enterprise-contract/ec-cli/internal/output"
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+)
+
+func testReport(t *testing.T, showSuccesses bool) Report {
+	p, err := policy.NewInputPolicy(context.Background(), `{"sources": [{"policy": ["github.com/org/policy"]}]}`, "2022-11-23T16:30:00Z")
+	require.NoError(t, err)
+
+	r := NewReport(p, showSuccesses)
+	r.Add(output.Output{PolicyCheck: evaluator.CheckResults{
+		{
+			CheckResult: conftest.CheckResult{
+				FileName: "/path/to/b.yaml",
+				Failures: []conftest.Result{{Message: "not enough spam", Metadata: map[string]interface{}{"code": "spam.count"}}},
+			},
+		},
+		{
+			CheckResult: conftest.CheckResult{
+				FileName: "/path/to/a.yaml",
+				Warnings: []conftest.Result{{Message: "running low on eggs"}},
+			},
+			Successes: []conftest.Result{{Message: "Pass", Metadata: map[string]interface{}{"code": "ham.present"}}},
+		},
+	}})
+	r.Add(output.Output{PolicyCheck: evaluator.CheckResults{
+		{
+			CheckResult: conftest.CheckResult{
+				FileName: "/path/to/a.yaml",
+				Warnings: []conftest.Result{{Message: "bacon is too crispy"}},
+			},
+		},
+	}})
+
+	return r
+}
+
+func TestReport(t *testing.T) {
+	r := testReport(t, false)
+
+	fs := afero.NewMemMapFs()
+	buf := bytes.Buffer{}
+	require.NoError(t, r.Write("json", format.NewTargetParser(JSON, &buf, fs)))
+
+	assert.JSONEq(t, `{
+		"success": false,
+		"filepaths": [
+			{
+				"filepath": "/path/to/a.yaml",
+				"violations": [],
+				"warnings": [{"msg": "running low on eggs"}, {"msg": "bacon is too crispy"}],
+				"success": true
+			},
+			{
+				"filepath": "/path/to/b.yaml",
+				"violations": [{"msg": "not enough spam", "metadata": {"code": "spam.count"}}],
+				"warnings": [],
+				"success": false
+			}
+		],
+		"policy": {"sources": [{"policy": ["github.com/org/policy"]}]},
+		"ec-version": "development",
+		"effective-time": "2022-11-23T16:30:00Z"
+	}`, buf.String())
+}
+
+func TestReportSuccesses(t *testing.T) {
+	r := testReport(t, true)
+
+	assert.Equal(t, []conftest.Result{{Message: "Pass", Metadata: map[string]interface{}{"code": "ham.present"}}}, r.FilePaths[0].Successes)
+	assert.Empty(t, r.FilePaths[1].Successes)
+}
+
+func TestReportText(t *testing.T) {
+	r := testReport(t, false)
+
+	assert.Equal(t, heredoc.Doc(`
+		Success: FAIL
+		Inputs: 2, Violations: 1, Warnings: 2
+
+		Input: /path/to/a.yaml
+		Result: WARN (violations: 0, warnings: 2)
+		  Warning
+		    running low on eggs
+		  Warning
+		    bacon is too crispy
+
+		Input: /path/to/b.yaml
+		Result: FAIL (violations: 1, warnings: 0)
+		  Violation spam.count
+		    not enough spam
+	`), string(r.toText(false)))
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package input
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/enterprise-contract/ec-cli/internal/evaluation_target/input"
+	"github.com/enterprise-contract/ec-cli/internal/evaluator"
+	"github.com/enterprise-contract/ec-cli/internal/output"
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+var inputFile = input.NewInput
+
+// ValidateInput evaluates the JSON or YAML documents at the given paths, or
+// the documents in the given directories, against the policy. The policy
+// sources are fetched once for all of the documents.
+func ValidateInput(ctx context.Context, fpaths []string, p policy.Policy, detailed bool) (*output.Output, error) {
+	var paths []string
+	for _, fpath := range fpaths {
+		log.Debugf("Validating input %s", fpath)
+
+		found, err := detectInput(ctx, fpath)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, found...)
+	}
+
+	in, err := inputFile(ctx, paths, p)
+	if err != nil {
+		log.Debug("Failed to create input!")
+		return nil, err
+	}
+
+	for _, e := range in.Evaluators {
+		defer e.Destroy()
+	}
+
+	var allResults evaluator.CheckResults
+	for _, e := range in.Evaluators {
+		results, _, err := e.Evaluate(ctx, paths)
+		if err != nil {
+			log.Debug("Problem running conftest policy check!")
+			return nil, err
+		}
+		allResults = append(allResults, results...)
+	}
+
+	log.Debug("Conftest policy check complete")
+	out := &output.Output{Detailed: detailed, Policy: p}
+	out.SetPolicyCheck(allResults)
+
+	return out, nil
+}
+
+// detectInput returns the given path if it is a file, or the JSON and YAML
+// files within it, and its subdirectories, if it is a directory.
+func detectInput(ctx context.Context, fpath string) ([]string, error) {
+	fs := utils.FS(ctx)
+
+	dir, err := afero.IsDir(fs, fpath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !dir {
+		return []string{fpath}, nil
+	}
+
+	paths, err := utils.WalkJsonOrYamlFiles(fs, fpath, func(string) bool { return true })
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("the directory %s contains no JSON or YAML files", fpath)
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package input
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/open-policy-agent/conftest/output"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/evaluation_target/input"
+	"github.com/enterprise-contract/ec-cli/internal/evaluator"
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+type mockEvaluator struct {
+	err error
+}
+
+func (e mockEvaluator) Evaluate(_ context.Context, inputs []string) (evaluator.CheckResults, evaluator.Data, error) {
+	if e.err != nil {
+		return nil, nil, e.err
+	}
+
+	results := evaluator.CheckResults{}
+	for _, i := range inputs {
+		results = append(results, evaluator.CheckResult{CheckResult: output.CheckResult{FileName: i}})
+	}
+
+	return results, nil, nil
+}
+
+func (e mockEvaluator) Destroy() {
+}
+
+func (e mockEvaluator) CapabilitiesPath() string {
+	return ""
+}
+
+func TestValidateInput(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/inputs/a.json", []byte(`{}`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/inputs/b.yaml", []byte(`a: 1`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/inputs/README.md", []byte(`# Inputs`), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/inputs/nested/c.yml", []byte(`c: 3`), 0644))
+	require.NoError(t, fs.MkdirAll("/empty", 0755))
+	ctx := utils.WithFS(context.Background(), fs)
+
+	p, err := policy.NewInputPolicy(ctx, "", policy.Now)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		fpaths   []string
+		evalErr  error
+		err      error
+		expected []string
+	}{
+		{
+			name:     "file",
+			fpaths:   []string{"/inputs/a.json"},
+			expected: []string{"/inputs/a.json"},
+		},
+		{
+			name:     "directory",
+			fpaths:   []string{"/inputs"},
+			expected: []string{"/inputs/a.json", "/inputs/b.yaml", "/inputs/nested/c.yml"},
+		},
+		{
+			name:     "multiple",
+			fpaths:   []string{"/inputs/b.yaml", "/inputs/a.json"},
+			expected: []string{"/inputs/b.yaml", "/inputs/a.json"},
+		},
+		{
+			name:   "empty directory",
+			fpaths: []string{"/empty"},
+			err:    errors.New("the directory /empty contains no JSON or YAML files"),
+		},
+		{
+			name:    "evaluation error",
+			fpaths:  []string{"/inputs/a.json"},
+			evalErr: errors.New("kaboom!"),
+			err:     errors.New("kaboom!"),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			built := 0
+			inputFile = func(_ context.Context, paths []string, _ policy.Policy) (*input.Input, error) {
+				built++
+				return &input.Input{Paths: paths, Evaluators: []evaluator.Evaluator{mockEvaluator{c.evalErr}}}, nil
+			}
+			t.Cleanup(func() { inputFile = input.NewInput })
+
+			out, err := ValidateInput(ctx, c.fpaths, p, false)
+			if c.err != nil {
+				assert.EqualError(t, err, c.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			// the evaluators are built once regardless of the number of paths
+			assert.Equal(t, 1, built)
+
+			var files []string
+			for _, r := range out.PolicyCheck {
+				files = append(files, r.FileName)
+			}
+			assert.Equal(t, c.expected, files)
+		})
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/open-policy-agent/conftest/output"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
@@ -86,6 +88,8 @@ type Output struct {
 	ImageSignatureCheck       VerificationStatus     `json:"imageSignatureCheck"`
 	AttestationSignatureCheck VerificationStatus     `json:"attestationSignatureCheck"`
 	AttestationSyntaxCheck    VerificationStatus     `json:"attestationSyntaxCheck"`
+	AttestationSyntaxChecks   []VerificationStatus   `json:"attestationSyntaxChecks,omitempty"`
+	VerificationSummaryCheck  *VerificationStatus    `json:"verificationSummaryCheck,omitempty"`
 	PolicyCheck               evaluator.CheckResults `json:"policyCheck"`
 	ExitCode                  int                    `json:"-"`
 	Signatures                []EntitySignature      `json:"signatures,omitempty"`
@@ -93,6 +97,9 @@ type Output struct {
 	Detailed                  bool                   `json:"-"`
 	Data                      []evaluator.Data       `json:"-"`
 	Policy                    policy.Policy          `json:"-"`
+	// Rules holds the metadata of the rules of the reported results, keyed by
+	// the rule code, regardless of the metadata kept in the results
+	Rules map[string]map[string]interface{} `json:"-"`
 }
 
 // SetImageAccessibleCheck sets the passed and result.message fields of the ImageAccessibleCheck to the given values.
@@ -112,9 +119,7 @@ func (o *Output) SetImageAccessibleCheckFromError(err error) {
 		log.Debugf("%s. Error: %s", message, err.Error())
 	}
 	result := &output.Result{Message: message, Metadata: metadata}
-	if !o.Detailed {
-		keepSomeMetadataSingle(*result)
-	}
+	o.keepSomeMetadata(*result)
 	o.ImageAccessibleCheck.Result = result
 }
 
@@ -136,9 +141,7 @@ func (o *Output) SetImageSignatureCheckFromError(err error) {
 		log.Debug(message)
 	}
 	result := &output.Result{Message: message, Metadata: metadata}
-	if !o.Detailed {
-		keepSomeMetadataSingle(*result)
-	}
+	o.keepSomeMetadata(*result)
 	o.ImageSignatureCheck.Result = result
 }
 
@@ -160,9 +163,7 @@ func (o *Output) SetAttestationSignatureCheckFromError(err error) {
 		log.Debug(message)
 	}
 	result := &output.Result{Message: message, Metadata: metadata}
-	if !o.Detailed {
-		keepSomeMetadataSingle(*result)
-	}
+	o.keepSomeMetadata(*result)
 	o.AttestationSignatureCheck.Result = result
 }
 
@@ -184,12 +185,91 @@ func (o *Output) SetAttestationSyntaxCheckFromError(err error) {
 		log.Debug(message)
 	}
 	result := &output.Result{Message: message, Metadata: metadata}
-	if !o.Detailed {
-		keepSomeMetadataSingle(*result)
-	}
+	o.keepSomeMetadata(*result)
 	o.AttestationSyntaxCheck.Result = result
 }
 
+// SyntaxCheck holds the outcome of validating a single attestation against
+// the JSON schema registered for its predicate type.
+type SyntaxCheck struct {
+	PredicateType string
+	SchemaID      string
+	Errors        []string
+}
+
+// SetAttestationSyntaxChecks records the outcome of the syntax check of each
+// attestation, each outcome is reported as a separate result. If the syntax
+// check could not be performed at all, as signified by the err parameter, or
+// no attestation was checked, a single result is reported instead.
+func (o *Output) SetAttestationSyntaxChecks(checks []SyntaxCheck, err error) {
+	if err != nil || len(checks) == 0 {
+		o.SetAttestationSyntaxCheckFromError(err)
+		return
+	}
+
+	failed := 0
+	o.AttestationSyntaxChecks = make([]VerificationStatus, 0, len(checks))
+	for _, check := range checks {
+		metadata := map[string]interface{}{
+			"code":           "builtin.attestation.syntax_check",
+			"title":          "Attestation syntax check passed",
+			"predicate_type": check.PredicateType,
+			"schema_id":      check.SchemaID,
+		}
+
+		status := VerificationStatus{Passed: len(check.Errors) == 0}
+		var message string
+		if status.Passed {
+			message = "Pass"
+			log.Debugf("Attestation syntax check passed for predicate type %s", check.PredicateType)
+		} else {
+			failed++
+			message = fmt.Sprintf("Attestation syntax check failed for predicate type %s: %s",
+				check.PredicateType, strings.Join(check.Errors, ", "))
+			log.Debug(message)
+		}
+
+		result := &output.Result{Message: message, Metadata: metadata}
+		o.keepSomeMetadata(*result)
+		status.Result = result
+		o.AttestationSyntaxChecks = append(o.AttestationSyntaxChecks, status)
+	}
+
+	// the aggregate check summarizes the checks of all attestations, it is
+	// not reported along with them in violations and successes
+	message := "Pass"
+	if failed > 0 {
+		message = fmt.Sprintf("Attestation syntax check failed for %d of %d attestations", failed, len(checks))
+	}
+	result := &output.Result{Message: message, Metadata: map[string]interface{}{
+		"code":  "builtin.attestation.syntax_check",
+		"title": "Attestation syntax check passed",
+	}}
+	o.keepSomeMetadata(*result)
+	o.AttestationSyntaxCheck = VerificationStatus{Passed: failed == 0, Result: result}
+}
+
+// SetVerificationSummaryCheck records that the image passed the validation
+// according to a trusted Verification Summary Attestation created at the given
+// time. The signature and syntax checks are considered passed as they were
+// performed when the attestation was created.
+func (o *Output) SetVerificationSummaryCheck(timeVerified time.Time) {
+	metadata := map[string]interface{}{
+		"code":  "builtin.vsa.verified",
+		"title": "Image verified by a trusted Verification Summary Attestation",
+	}
+	message := fmt.Sprintf("Pass, according to the Verification Summary Attestation created on %s", timeVerified.Format(time.RFC3339))
+	log.Debug(message)
+
+	o.ImageSignatureCheck.Passed = true
+	o.AttestationSignatureCheck.Passed = true
+	o.AttestationSyntaxCheck.Passed = true
+
+	result := &output.Result{Message: message, Metadata: metadata}
+	o.keepSomeMetadata(*result)
+	o.VerificationSummaryCheck = &VerificationStatus{Passed: true, Result: result}
+}
+
 // SetPolicyCheck sets the PolicyCheck and ExitCode to the results and exit code of the Results
 func (o *Output) SetPolicyCheck(results evaluator.CheckResults) {
 	for r := range results {
@@ -199,27 +279,57 @@ func (o *Output) SetPolicyCheck(results evaluator.CheckResults) {
 
 		results[r].Queries = nil
 
-		if !o.Detailed {
-			keepSomeMetadata(results[r].Exceptions)
-			keepSomeMetadata(results[r].Failures)
-			keepSomeMetadata(results[r].Successes)
-			keepSomeMetadata(results[r].Skipped)
-			keepSomeMetadata(results[r].Warnings)
-		}
+		o.keepSomeMetadata(results[r].Exceptions...)
+		o.keepSomeMetadata(results[r].Failures...)
+		o.keepSomeMetadata(results[r].Successes...)
+		o.keepSomeMetadata(results[r].Skipped...)
+		o.keepSomeMetadata(results[r].Warnings...)
+		o.keepSomeMetadata(results[r].FutureViolations...)
+		o.keepSomeMetadata(results[r].Exempted...)
 	}
 	o.PolicyCheck = results
 	o.ExitCode = output.ExitCode(results.ToConftestResults())
 }
 
-func keepSomeMetadata(results []output.Result) {
-	for i := range results {
-		keepSomeMetadataSingle(results[i])
+// keepSomeMetadata records the metadata of the rules of the results in Rules
+// and, unless detailed output was requested, removes all but the essential
+// metadata from the results.
+func (o *Output) keepSomeMetadata(results ...output.Result) {
+	for _, result := range results {
+		o.recordRule(result)
+		if !o.Detailed {
+			keepSomeMetadataSingle(result)
+		}
+	}
+}
+
+// recordRule keeps a copy of the metadata of the rule the result pertains to,
+// the first result seen for a rule is used.
+func (o *Output) recordRule(result output.Result) {
+	code, ok := result.Metadata["code"].(string)
+	if !ok {
+		return
+	}
+
+	if o.Rules == nil {
+		o.Rules = map[string]map[string]interface{}{}
+	}
+
+	if _, ok := o.Rules[code]; ok {
+		return
 	}
+
+	metadata := make(map[string]interface{}, len(result.Metadata))
+	for key, value := range result.Metadata {
+		metadata[key] = value
+	}
+	o.Rules[code] = metadata
 }
 
 func keepSomeMetadataSingle(result output.Result) {
 	for key := range result.Metadata {
-		if key == "code" || key == "effective_on" {
+		switch key {
+		case "code", "effective_on", "exception":
 			continue
 		}
 		delete(result.Metadata, key)
@@ -241,7 +351,12 @@ func (o Output) Violations() []output.Result {
 	violations = o.ImageSignatureCheck.addToViolations(violations)
 	violations = o.ImageAccessibleCheck.addToViolations(violations)
 	violations = o.AttestationSignatureCheck.addToViolations(violations)
-	violations = o.AttestationSyntaxCheck.addToViolations(violations)
+	if len(o.AttestationSyntaxChecks) == 0 {
+		violations = o.AttestationSyntaxCheck.addToViolations(violations)
+	}
+	for _, check := range o.AttestationSyntaxChecks {
+		violations = check.addToViolations(violations)
+	}
 	violations = o.addCheckResultsToViolations(violations)
 
 	violations = sortResults(violations)
@@ -259,6 +374,30 @@ func (o Output) Warnings() []output.Result {
 	return warnings
 }
 
+// FutureViolations aggregates and returns all failures of rules that are not
+// yet effective.
+func (o Output) FutureViolations() []output.Result {
+	futureViolations := make([]output.Result, 0, 10)
+	for _, result := range o.PolicyCheck {
+		futureViolations = append(futureViolations, result.FutureViolations...)
+	}
+
+	futureViolations = sortResults(futureViolations)
+	return futureViolations
+}
+
+// Exempted aggregates and returns all failures and warnings covered by a
+// policy exception.
+func (o Output) Exempted() []output.Result {
+	exempted := make([]output.Result, 0, 10)
+	for _, result := range o.PolicyCheck {
+		exempted = append(exempted, result.Exempted...)
+	}
+
+	exempted = sortResults(exempted)
+	return exempted
+}
+
 // Successes aggregates and returns all successes.
 func (o Output) Successes() []output.Result {
 	successes := make([]output.Result, 0, 10)
@@ -268,7 +407,15 @@ func (o Output) Successes() []output.Result {
 
 	successes = o.ImageSignatureCheck.addToSuccesses(successes)
 	successes = o.AttestationSignatureCheck.addToSuccesses(successes)
-	successes = o.AttestationSyntaxCheck.addToSuccesses(successes)
+	if len(o.AttestationSyntaxChecks) == 0 {
+		successes = o.AttestationSyntaxCheck.addToSuccesses(successes)
+	}
+	for _, check := range o.AttestationSyntaxChecks {
+		successes = check.addToSuccesses(successes)
+	}
+	if o.VerificationSummaryCheck != nil {
+		successes = o.VerificationSummaryCheck.addToSuccesses(successes)
+	}
 
 	successes = sortResults(successes)
 	return successes
@@ -23,6 +23,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/open-policy-agent/conftest/output"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
@@ -1070,3 +1071,96 @@ func TestSetAttestationSyntaxCheckFromError(t *testing.T) {
 		})
 	}
 }
+
+func TestSetAttestationSyntaxChecks(t *testing.T) {
+	o := Output{Detailed: true}
+	o.SetAttestationSyntaxChecks([]SyntaxCheck{
+		{PredicateType: "https://slsa.dev/provenance/v0.2", SchemaID: "https://slsa.dev/provenance/v0.2"},
+		{PredicateType: "https://slsa.dev/provenance/v1", SchemaID: "https://slsa.dev/provenance/v1", Errors: []string{"/predicate: missing buildDefinition"}},
+	}, nil)
+
+	assert.False(t, o.AttestationSyntaxCheck.Passed)
+	assert.Equal(t, "Attestation syntax check failed for 1 of 2 attestations", o.AttestationSyntaxCheck.Result.Message)
+	assert.Len(t, o.AttestationSyntaxChecks, 2)
+
+	assert.True(t, o.AttestationSyntaxChecks[0].Passed)
+	assert.Equal(t, "Pass", o.AttestationSyntaxChecks[0].Result.Message)
+	assert.Equal(t, "https://slsa.dev/provenance/v0.2", o.AttestationSyntaxChecks[0].Result.Metadata["predicate_type"])
+	assert.Equal(t, "Attestation syntax check passed", o.AttestationSyntaxChecks[0].Result.Metadata["title"])
+
+	assert.False(t, o.AttestationSyntaxChecks[1].Passed)
+	assert.Equal(t, "Attestation syntax check failed for predicate type https://slsa.dev/provenance/v1: /predicate: missing buildDefinition",
+		o.AttestationSyntaxChecks[1].Result.Message)
+
+	assert.Equal(t, []output.Result{*o.AttestationSyntaxChecks[1].Result}, o.Violations())
+	assert.Equal(t, []output.Result{*o.AttestationSyntaxChecks[0].Result}, o.Successes())
+}
+
+func TestSetAttestationSyntaxChecksFromError(t *testing.T) {
+	o := Output{}
+	o.SetAttestationSyntaxChecks(nil, errors.New("kaboom!"))
+
+	assert.False(t, o.AttestationSyntaxCheck.Passed)
+	assert.Empty(t, o.AttestationSyntaxChecks)
+	assert.Equal(t, "Attestation syntax check failed: kaboom!", o.AttestationSyntaxCheck.Result.Message)
+}
+
+func TestSetVerificationSummaryCheck(t *testing.T) {
+	o := Output{}
+	o.SetImageAccessibleCheckFromError(nil)
+	o.SetVerificationSummaryCheck(time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	assert.True(t, o.ImageSignatureCheck.Passed)
+	assert.True(t, o.AttestationSignatureCheck.Passed)
+	assert.True(t, o.AttestationSyntaxCheck.Passed)
+	assert.Empty(t, o.Violations())
+
+	expected := output.Result{
+		Message:  "Pass, according to the Verification Summary Attestation created on 2023-05-01T12:00:00Z",
+		Metadata: map[string]interface{}{"code": "builtin.vsa.verified"},
+	}
+	assert.Equal(t, &VerificationStatus{Passed: true, Result: &expected}, o.VerificationSummaryCheck)
+	assert.Equal(t, []output.Result{expected}, o.Successes())
+}
+
+func Test_FutureViolations(t *testing.T) {
+	o := Output{
+		PolicyCheck: evaluator.CheckResults{
+			{
+				FutureViolations: []output.Result{
+					{Message: "b", Metadata: map[string]interface{}{"code": "b", "effective_on": "2099-01-01T00:00:00Z"}},
+				},
+			},
+			{
+				FutureViolations: []output.Result{
+					{Message: "a", Metadata: map[string]interface{}{"code": "a", "effective_on": "2099-01-01T00:00:00Z"}},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, []output.Result{
+		{Message: "a", Metadata: map[string]interface{}{"code": "a", "effective_on": "2099-01-01T00:00:00Z"}},
+		{Message: "b", Metadata: map[string]interface{}{"code": "b", "effective_on": "2099-01-01T00:00:00Z"}},
+	}, o.FutureViolations())
+	assert.Empty(t, o.Violations())
+	assert.Empty(t, o.Warnings())
+}
+
+func TestSetPolicyCheckKeepsRuleMetadata(t *testing.T) {
+	o := Output{}
+	o.SetPolicyCheck(evaluator.CheckResults{
+		{
+			CheckResult: output.CheckResult{
+				Failures: []output.Result{
+					{Message: "a", Metadata: map[string]interface{}{"code": "a", "title": "Rule A", "description": "Describes A"}},
+				},
+			},
+		},
+	})
+
+	assert.Equal(t, map[string]interface{}{"code": "a"}, o.Violations()[0].Metadata)
+	assert.Equal(t, map[string]map[string]interface{}{
+		"a": {"code": "a", "title": "Rule A", "description": "Describes A"},
+	}, o.Rules)
+}
@@ -0,0 +1,136 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"sigs.k8s.io/yaml"
+)
+
+// Exception exempts the results of a rule from being reported as violations
+// or warnings until it expires. It is provided in the exceptions attribute of
+// the EnterpriseContractPolicy spec given as JSON or YAML, i.e. not when the
+// policy is fetched from the cluster as the custom resource has no such
+// attribute.
+type Exception struct {
+	// Value matches the rule in the same way as the include and exclude
+	// configuration does, e.g. "pkg.rule" or "pkg.rule:term"
+	Value string `json:"value"`
+	// Components limits the exception to the components with these names
+	Components []string `json:"components,omitempty"`
+	// ImageRepositories limits the exception to images from the repositories
+	// matching these patterns, e.g. "registry.io/org/*"
+	ImageRepositories []string `json:"imageRepositories,omitempty"`
+	// Expires holds the date, or RFC3339 timestamp, from which the exception
+	// no longer applies
+	Expires       string `json:"expires"`
+	Justification string `json:"justification"`
+	Approver      string `json:"approver"`
+
+	expires time.Time
+}
+
+// IsActive returns true if the exception has not expired at the given time.
+func (e Exception) IsActive(at time.Time) bool {
+	return at.Before(e.expires)
+}
+
+// AppliesTo returns true if the exception is not limited to specific
+// components or image repositories, or if the given component name or image
+// repository is one of those it is limited to.
+func (e Exception) AppliesTo(component, imageRepository string) bool {
+	if len(e.Components) == 0 && len(e.ImageRepositories) == 0 {
+		return true
+	}
+
+	return inScope(e.Components, e.ImageRepositories, component, imageRepository)
+}
+
+// inScope returns true if the component is one of the given components, or
+// the image repository matches one of the given image repository patterns.
+func inScope(components, imageRepositories []string, component, imageRepository string) bool {
+	for _, c := range components {
+		if c == component {
+			return true
+		}
+	}
+
+	for _, pattern := range imageRepositories {
+		if ok, err := path.Match(pattern, imageRepository); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseExceptions reads the exceptions from the JSON or YAML policy
+// specification and checks that each one is complete.
+func parseExceptions(policyRef string) (exceptions []Exception, allErrors error) {
+	spec := struct {
+		Exceptions []Exception `json:"exceptions"`
+	}{}
+
+	if err := yaml.Unmarshal([]byte(policyRef), &spec); err != nil {
+		return nil, fmt.Errorf("unable to parse policy exceptions: %w", err)
+	}
+
+	for i, e := range spec.Exceptions {
+		if e.Value == "" {
+			allErrors = multierror.Append(allErrors, fmt.Errorf("exception %d: value is required", i))
+		}
+		if e.Justification == "" {
+			allErrors = multierror.Append(allErrors, fmt.Errorf("exception %d: justification is required", i))
+		}
+		if e.Approver == "" {
+			allErrors = multierror.Append(allErrors, fmt.Errorf("exception %d: approver is required", i))
+		}
+
+		expires, err := parseExpiry(e.Expires)
+		if err != nil {
+			allErrors = multierror.Append(allErrors, fmt.Errorf("exception %d: %w", i, err))
+			continue
+		}
+		spec.Exceptions[i].expires = expires
+	}
+
+	if allErrors != nil {
+		return nil, allErrors
+	}
+
+	return spec.Exceptions, nil
+}
+
+func parseExpiry(expires string) (time.Time, error) {
+	if expires == "" {
+		return time.Time{}, fmt.Errorf("expires is required")
+	}
+
+	if t, err := time.Parse(time.RFC3339, expires); err == nil {
+		return t, nil
+	}
+
+	if t, err := time.Parse(DateFormat, expires); err == nil {
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid expires value %q, expecting a date (%s) or a RFC3339 timestamp", expires, DateFormat)
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package policy
+
+import (
+	"testing"
+	"time"
+
+	hd "github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExceptions(t *testing.T) {
+	exceptions, err := parseExceptions(hd.Doc(`
+		publicKey: key
+		exceptions:
+		- value: pkg.rule
+		  expires: "2023-06-01"
+		  justification: waiting on upstream fix
+		  approver: security@example.com
+		- value: other.rule:term
+		  components: [web]
+		  imageRepositories: ["registry.io/org/*"]
+		  expires: "2023-06-01T12:00:00Z"
+		  justification: false positive
+		  approver: security@example.com
+	`))
+	require.NoError(t, err)
+	require.Len(t, exceptions, 2)
+
+	assert.Equal(t, "pkg.rule", exceptions[0].Value)
+	assert.Equal(t, time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), exceptions[0].expires)
+	assert.Equal(t, []string{"web"}, exceptions[1].Components)
+	assert.Equal(t, time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC), exceptions[1].expires)
+}
+
+func TestParseExceptionsNone(t *testing.T) {
+	exceptions, err := parseExceptions(`{"publicKey": "key"}`)
+	require.NoError(t, err)
+	assert.Empty(t, exceptions)
+}
+
+func TestParseExceptionsInvalid(t *testing.T) {
+	_, err := parseExceptions(hd.Doc(`
+		exceptions:
+		- value: pkg.rule
+		  expires: next week
+		- expires: "2023-06-01"
+	`))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `exception 0: invalid expires value "next week"`)
+	assert.ErrorContains(t, err, "exception 0: justification is required")
+	assert.ErrorContains(t, err, "exception 1: value is required")
+	assert.ErrorContains(t, err, "exception 1: approver is required")
+}
+
+func TestExceptionIsActive(t *testing.T) {
+	e := Exception{expires: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)}
+
+	assert.True(t, e.IsActive(time.Date(2023, 5, 31, 23, 59, 59, 0, time.UTC)))
+	assert.False(t, e.IsActive(time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, e.IsActive(time.Date(2023, 7, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestExceptionAppliesTo(t *testing.T) {
+	cases := []struct {
+		name            string
+		exception       Exception
+		component       string
+		imageRepository string
+		expected        bool
+	}{
+		{
+			name:      "unscoped",
+			exception: Exception{},
+			component: "web",
+			expected:  true,
+		},
+		{
+			name:      "matching component",
+			exception: Exception{Components: []string{"api", "web"}},
+			component: "web",
+			expected:  true,
+		},
+		{
+			name:      "other component",
+			exception: Exception{Components: []string{"api"}},
+			component: "web",
+			expected:  false,
+		},
+		{
+			name:            "matching image repository",
+			exception:       Exception{ImageRepositories: []string{"registry.io/org/*"}},
+			imageRepository: "registry.io/org/web",
+			expected:        true,
+		},
+		{
+			name:            "other image repository",
+			exception:       Exception{ImageRepositories: []string{"registry.io/org/*"}},
+			imageRepository: "registry.io/other/web",
+			expected:        false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			assert.Equal(t, c.expected, c.exception.AppliesTo(c.component, c.imageRepository))
+		})
+	}
+}
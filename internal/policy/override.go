@@ -0,0 +1,139 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	ecc "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
+	"github.com/hashicorp/go-multierror"
+	"sigs.k8s.io/yaml"
+)
+
+// ComponentOverride replaces the configuration, and adds to the rule data, of
+// the policy when validating the components with the given names or the
+// images from repositories matching the given patterns. It is provided in the
+// componentOverrides attribute of the EnterpriseContractPolicy spec given as
+// JSON or YAML, i.e. not when the policy is fetched from the cluster as the
+// custom resource has no such attribute.
+type ComponentOverride struct {
+	// Components are the names of the components the override applies to
+	Components []string `json:"components,omitempty"`
+	// ImageRepositories are the patterns, e.g. "registry.io/org/*", of the
+	// image repositories the override applies to
+	ImageRepositories []string `json:"imageRepositories,omitempty"`
+	// Configuration replaces the configuration of the policy
+	Configuration *ecc.EnterpriseContractPolicyConfiguration `json:"configuration,omitempty"`
+	// RuleData is added to the rule data of each source group, replacing the
+	// values of the same keys
+	RuleData map[string]interface{} `json:"ruleData,omitempty"`
+}
+
+// AppliesTo returns true if the given component name or image repository is
+// one of those the override is limited to.
+func (o ComponentOverride) AppliesTo(component, imageRepository string) bool {
+	return inScope(o.Components, o.ImageRepositories, component, imageRepository)
+}
+
+// ForComponent returns a copy of the policy with the overrides applying to
+// the component with the given name and image repository applied, in the
+// order they are provided. The policy is returned as is when no override
+// applies.
+func (p *policy) ForComponent(component, imageRepository string) (Policy, error) {
+	var applicable []ComponentOverride
+	for _, o := range p.overrides {
+		if o.AppliesTo(component, imageRepository) {
+			applicable = append(applicable, o)
+		}
+	}
+
+	if len(applicable) == 0 {
+		return p, nil
+	}
+
+	c := *p
+	c.Sources = append([]ecc.Source{}, p.Sources...)
+
+	for _, o := range applicable {
+		if o.Configuration != nil {
+			c.Configuration = o.Configuration
+		}
+
+		if len(o.RuleData) == 0 {
+			continue
+		}
+
+		for i := range c.Sources {
+			if err := mergeRuleData(&c.Sources[i], o.RuleData); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &c, nil
+}
+
+// mergeRuleData sets the rule data of the source to its current rule data
+// with the given values added to it.
+func mergeRuleData(s *ecc.Source, values map[string]interface{}) error {
+	merged := map[string]interface{}{}
+	if s.RuleData != nil && len(s.RuleData.Raw) > 0 {
+		if err := json.Unmarshal(s.RuleData.Raw, &merged); err != nil {
+			return fmt.Errorf("unable to parse rule data of source group %q: %w", s.Name, err)
+		}
+	}
+
+	for k, v := range values {
+		merged[k] = v
+	}
+
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return err
+	}
+
+	// Unmarshalled, rather than assigned, so that a new rule data value is
+	// allocated instead of modifying the one shared with the original policy
+	s.RuleData = nil
+	return json.Unmarshal(raw, &s.RuleData)
+}
+
+// parseOverrides reads the component overrides from the JSON or YAML policy
+// specification and checks that each one is limited to some components or
+// image repositories.
+func parseOverrides(policyRef string) (overrides []ComponentOverride, allErrors error) {
+	spec := struct {
+		ComponentOverrides []ComponentOverride `json:"componentOverrides"`
+	}{}
+
+	if err := yaml.Unmarshal([]byte(policyRef), &spec); err != nil {
+		return nil, fmt.Errorf("unable to parse component overrides: %w", err)
+	}
+
+	for i, o := range spec.ComponentOverrides {
+		if len(o.Components) == 0 && len(o.ImageRepositories) == 0 {
+			allErrors = multierror.Append(allErrors, fmt.Errorf("component override %d: components or imageRepositories is required", i))
+		}
+	}
+
+	if allErrors != nil {
+		return nil, allErrors
+	}
+
+	return spec.ComponentOverrides, nil
+}
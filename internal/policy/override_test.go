@@ -0,0 +1,110 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package policy
+
+import (
+	"context"
+	"testing"
+
+	hd "github.com/MakeNowJust/heredoc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const overridesPolicy = `
+sources:
+- name: default
+  policy: [oci::registry.io/policy:latest]
+  ruleData:
+    allowed_registries: [registry.io]
+    required_tasks: [buildah]
+configuration:
+  include: ["@minimal"]
+componentOverrides:
+- components: [base-image]
+  configuration:
+    include: ["@redhat"]
+    exclude: [test]
+- imageRepositories: ["registry.io/bundles/*"]
+  ruleData:
+    required_tasks: []
+`
+
+func TestForComponent(t *testing.T) {
+	p, err := NewInertPolicy(context.Background(), overridesPolicy)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name            string
+		component       string
+		imageRepository string
+		include         []string
+		exclude         []string
+		ruleData        string
+	}{
+		{
+			name:            "no override",
+			component:       "web",
+			imageRepository: "registry.io/apps/web",
+			include:         []string{"@minimal"},
+			ruleData:        `{"allowed_registries":["registry.io"],"required_tasks":["buildah"]}`,
+		},
+		{
+			name:            "configuration by component name",
+			component:       "base-image",
+			imageRepository: "registry.io/base/image",
+			include:         []string{"@redhat"},
+			exclude:         []string{"test"},
+			ruleData:        `{"allowed_registries":["registry.io"],"required_tasks":["buildah"]}`,
+		},
+		{
+			name:            "rule data by image repository",
+			component:       "operator-bundle",
+			imageRepository: "registry.io/bundles/operator",
+			include:         []string{"@minimal"},
+			ruleData:        `{"allowed_registries":["registry.io"],"required_tasks":[]}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			o, err := p.ForComponent(c.component, c.imageRepository)
+			require.NoError(t, err)
+
+			spec := o.Spec()
+			assert.Equal(t, c.include, spec.Configuration.Include)
+			assert.Equal(t, c.exclude, spec.Configuration.Exclude)
+			assert.JSONEq(t, c.ruleData, string(spec.Sources[0].RuleData.Raw))
+		})
+	}
+
+	// the original policy is left as is
+	spec := p.Spec()
+	assert.Equal(t, []string{"@minimal"}, spec.Configuration.Include)
+	assert.JSONEq(t, `{"allowed_registries":["registry.io"],"required_tasks":["buildah"]}`, string(spec.Sources[0].RuleData.Raw))
+}
+
+func TestParseOverridesUnscoped(t *testing.T) {
+	_, err := parseOverrides(hd.Doc(`
+		componentOverrides:
+		- configuration:
+		    include: ["*"]
+	`))
+	assert.ErrorContains(t, err, "component override 0: components or imageRepositories is required")
+}
@@ -59,9 +59,12 @@ type Policy interface {
 	WithSpec(spec ecc.EnterpriseContractPolicySpec) Policy
 	Spec() ecc.EnterpriseContractPolicySpec
 	EffectiveTime() time.Time
+	ChosenTime() string
 	AttestationTime(time.Time)
 	Identity() cosign.Identity
 	Keyless() bool
+	Exceptions() []Exception
+	ForComponent(component, imageRepository string) (Policy, error)
 }
 
 type policy struct {
@@ -71,7 +74,9 @@ type policy struct {
 	effectiveTime   *time.Time
 	attestationTime *time.Time
 	// TODO: Move these to ecc.EnterpriseContractPolicySpec
-	identity cosign.Identity
+	identity   cosign.Identity
+	exceptions []Exception
+	overrides  []ComponentOverride
 }
 
 // PublicKeyPEM returns the PublicKey in PEM format.
@@ -106,6 +111,11 @@ func (p *policy) Identity() cosign.Identity {
 	return p.identity
 }
 
+// Exceptions returns the exceptions provided with the policy.
+func (p *policy) Exceptions() []Exception {
+	return p.exceptions
+}
+
 // Keyless returns whether or not the Policy uses the keyless workflow for verification.
 func (p *policy) Keyless() bool {
 	return keylessEnabled() && p.PublicKey == ""
@@ -144,6 +154,32 @@ func NewInertPolicy(ctx context.Context, policyRef string) (Policy, error) {
 	return &p, nil
 }
 
+// NewInputPolicy construct and return a new instance of Policy used to
+// validate arbitrary inputs, i.e. not images, so no public key or identity is
+// required.
+//
+// The policyRef parameter is expected to be either a JSON-encoded instance of
+// EnterpriseContractPolicySpec, or reference to the location of the EnterpriseContractPolicy
+// resource in Kubernetes using the format: [namespace/]name
+func NewInputPolicy(ctx context.Context, policyRef, effectiveTime string) (Policy, error) {
+	p := policy{
+		choosenTime: effectiveTime,
+		checkOpts:   &cosign.CheckOpts{},
+	}
+
+	if err := p.loadPolicy(ctx, policyRef); err != nil {
+		return nil, err
+	}
+
+	if efn, err := parseEffectiveTime(effectiveTime); err != nil {
+		return nil, err
+	} else {
+		p.effectiveTime = efn
+	}
+
+	return &p, nil
+}
+
 // NewPolicy construct and return a new instance of Policy.
 //
 // The policyRef parameter is expected to be either a JSON-encoded instance of
@@ -216,6 +252,18 @@ func (p *policy) loadPolicy(ctx context.Context, policyRef string) error {
 			log.Debugf("Problem parsing EnterpriseContractPolicySpec from %q", policyRef)
 			return fmt.Errorf("unable to parse EnterpriseContractPolicySpec: %w", err)
 		}
+
+		exceptions, err := parseExceptions(policyRef)
+		if err != nil {
+			return err
+		}
+		p.exceptions = exceptions
+
+		overrides, err := parseOverrides(policyRef)
+		if err != nil {
+			return err
+		}
+		p.overrides = overrides
 	} else {
 		log.Debug("Read EnterpriseContractPolicy as k8s resource")
 		k8s, err := kubernetes.NewClient(ctx)
@@ -261,6 +309,12 @@ func (p policy) EffectiveTime() time.Time {
 	return *p.effectiveTime
 }
 
+// ChosenTime returns the effective time as it was chosen, i.e. "now",
+// "attestation", or a date or timestamp, rather than the time it resolves to.
+func (p policy) ChosenTime() string {
+	return p.choosenTime
+}
+
 func isNow(choosenTime string) bool {
 	return strings.EqualFold(choosenTime, Now)
 }
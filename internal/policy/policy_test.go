@@ -33,6 +33,7 @@ import (
 	cosignSig "github.com/sigstore/cosign/v2/pkg/signature"
 	sigstoreSig "github.com/sigstore/sigstore/pkg/signature"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/yaml"
 
 	"github.com/enterprise-contract/ec-cli/internal/kubernetes"
@@ -216,6 +217,20 @@ func (c *FakeCosignClient) publicKeyFromKeyRef(context.Context, string) (sigstor
 	return cosignSig.LoadPublicKeyRaw([]byte(c.publicKey), crypto.SHA256)
 }
 
+func TestNewInputPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	p, err := NewInputPolicy(ctx, `{"sources": [{"policy": ["github.com/org/policy"]}]}`, "2022-11-23T16:30:00Z")
+	require.NoError(t, err)
+
+	assert.Equal(t, []ecc.Source{{Policy: []string{"github.com/org/policy"}}}, p.Spec().Sources)
+	assert.Equal(t, time.Date(2022, 11, 23, 16, 30, 0, 0, time.UTC), p.EffectiveTime())
+	assert.Empty(t, p.Spec().PublicKey)
+
+	_, err = NewInputPolicy(ctx, `{"sources": []}`, "not a time")
+	assert.Error(t, err)
+}
+
 func TestCheckOpts(t *testing.T) {
 	cases := []struct {
 		name            string
@@ -0,0 +1,215 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package source
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"regexp"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+const cacheKey key = 1
+
+// completeSuffix is appended to the directory of a persistently cached
+// source to mark that the download has completed
+const completeSuffix = ".complete"
+
+// pinned matches source URLs that reference immutable content, i.e. OCI
+// references by digest or git references by full commit SHA
+var pinned = regexp.MustCompile(`@sha256:[0-9a-f]{64}$|[?&]ref=[0-9a-f]{40}(&|$)`)
+
+// Cache holds the policy sources downloaded within a single run so that each
+// source is downloaded only once and shared, read-only, by all evaluations.
+// Optionally sources pinned to immutable content are kept in a persistent
+// directory so later runs can reuse them.
+type Cache struct {
+	// ctx is the context of the run, downloads are not tied to the context
+	// of any single evaluation as their outcome is shared by all of them
+	ctx        context.Context
+	fs         afero.Fs
+	runDir     string
+	persistDir string
+	mu         sync.Mutex
+	entries    map[string]*cacheEntry
+	// dirs holds the directories of the downloaded sources
+	dirs map[string]bool
+}
+
+type cacheEntry struct {
+	mu  sync.Mutex
+	dir string
+}
+
+// NewCache creates a Cache with a temporary directory for the current run.
+// Sources are downloaded using the given context, which should be scoped to
+// the run. When persistDir is not empty, sources pinned by digest or commit
+// SHA are stored in it and reused across runs. Invoke Cleanup to remove the
+// temporary directory.
+func NewCache(ctx context.Context, persistDir string) (*Cache, error) {
+	fs := utils.FS(ctx)
+	runDir, err := afero.TempDir(fs, afero.GetTempDir(fs, ""), "ec-policy-cache-")
+	if err != nil {
+		return nil, err
+	}
+
+	if persistDir != "" {
+		if err := fs.MkdirAll(persistDir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Cache{
+		ctx:        ctx,
+		fs:         fs,
+		runDir:     runDir,
+		persistDir: persistDir,
+		entries:    map[string]*cacheEntry{},
+		dirs:       map[string]bool{},
+	}, nil
+}
+
+// WithCache makes PolicyUrl sources download to and reuse the given Cache.
+func WithCache(ctx context.Context, c *Cache) context.Context {
+	return context.WithValue(ctx, cacheKey, c)
+}
+
+// IsShared reports whether dir was provided by the Cache set via WithCache,
+// i.e. it is kept outside of the work directory given to GetPolicy and
+// shared with other evaluations.
+func IsShared(ctx context.Context, dir string) bool {
+	c, ok := ctx.Value(cacheKey).(*Cache)
+	if !ok || c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.dirs[dir]
+}
+
+// Cleanup removes the sources downloaded in this run, the persistently cached
+// sources are kept.
+func (c *Cache) Cleanup() {
+	utils.CleanupWorkDir(c.fs, c.runDir)
+}
+
+// fetch returns the directory containing the source, downloading it only if
+// it hasn't already been downloaded, concurrent callers for the same source
+// wait for the single download to complete. Failed downloads are not
+// remembered, the next caller attempts the download again.
+func (c *Cache) fetch(kind, sourceUrl string, download func(ctx context.Context, dest string) error) (string, error) {
+	key := fmt.Sprintf("%x", sha256.Sum224([]byte(kind+"/"+sourceUrl)))
+
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	if !ok {
+		e = &cacheEntry{}
+		c.entries[key] = e
+	}
+	c.mu.Unlock()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.dir != "" {
+		return e.dir, nil
+	}
+
+	dir, err := c.populate(key, kind, sourceUrl, download)
+	if err != nil {
+		return "", err
+	}
+
+	e.dir = dir
+	c.mu.Lock()
+	c.dirs[dir] = true
+	c.mu.Unlock()
+
+	return dir, nil
+}
+
+func (c *Cache) populate(key, kind, sourceUrl string, download func(ctx context.Context, dest string) error) (string, error) {
+	if c.persistDir == "" || !pinned.MatchString(sourceUrl) {
+		dest := path.Join(c.runDir, kind, key)
+		return dest, download(c.ctx, dest)
+	}
+
+	dest := path.Join(c.persistDir, kind, key)
+	marker := dest + completeSuffix
+	if ok, err := afero.Exists(c.fs, marker); err == nil && ok {
+		log.Debugf("Using cached source %s from %s", sourceUrl, dest)
+		return dest, nil
+	}
+
+	// The source is downloaded into a temporary directory next to its entry
+	// and renamed into place once complete, so that concurrent ec processes
+	// sharing the cache directory never observe, or remove, each other's
+	// partial downloads
+	parent := path.Join(c.persistDir, kind)
+	if err := c.fs.MkdirAll(parent, 0o755); err != nil {
+		return "", err
+	}
+
+	tmp, err := afero.TempDir(c.fs, parent, key+".download-")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := c.fs.RemoveAll(tmp); err != nil {
+			log.Debugf("Unable to remove %s: %v", tmp, err)
+		}
+	}()
+
+	downloaded := path.Join(tmp, "source")
+	if err := download(c.ctx, downloaded); err != nil {
+		return "", err
+	}
+
+	if err := c.fs.Rename(downloaded, dest); err != nil {
+		if ok, err := afero.Exists(c.fs, marker); err == nil && ok {
+			// another process completed the same download first
+			log.Debugf("Using cached source %s from %s", sourceUrl, dest)
+			return dest, nil
+		}
+
+		// the entry is not marked as complete, e.g. left over by an older
+		// version that downloaded in place, it is moved out of the way to
+		// be removed along with the temporary directory
+		if err := c.fs.Rename(dest, path.Join(tmp, "stale")); err != nil {
+			return "", err
+		}
+
+		if err := c.fs.Rename(downloaded, dest); err != nil {
+			return "", err
+		}
+	}
+
+	if err := afero.WriteFile(c.fs, marker, []byte(sourceUrl), 0o644); err != nil {
+		log.Debugf("Unable to mark %s as cached: %v", dest, err)
+	}
+
+	return dest, nil
+}
@@ -0,0 +1,216 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package source
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/utils"
+)
+
+const pinnedUrl = "oci::registry.io/policy@sha256:4e388ab32b10dc8dbc7e28144f552830adc74787c1e2c0824032078a79f227fb"
+
+func TestCacheDownloadsOnce(t *testing.T) {
+	dl := mockDownloader{}
+	dl.On("Download", mock.Anything, "https://example.com/user/foo.git", false).Return(nil).Once()
+	ctx := usingDownloader(utils.WithFS(context.Background(), afero.NewMemMapFs()), &dl)
+
+	c, err := NewCache(ctx, "")
+	require.NoError(t, err)
+	defer c.Cleanup()
+
+	ctx = WithCache(ctx, c)
+
+	p := PolicyUrl{Url: "https://example.com/user/foo.git", Kind: "policy"}
+
+	dirs := make([]string, 10)
+	var wg sync.WaitGroup
+	for i := range dirs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dir, err := p.GetPolicy(ctx, "/tmp/ec-work-1234", false)
+			assert.NoError(t, err)
+			dirs[i] = dir
+		}(i)
+	}
+	wg.Wait()
+
+	for _, dir := range dirs {
+		assert.Equal(t, dirs[0], dir)
+	}
+	assert.True(t, strings.HasPrefix(dirs[0], c.runDir+"/policy/"))
+	assert.True(t, IsShared(ctx, dirs[0]))
+	assert.False(t, IsShared(ctx, "/tmp/ec-work-1234/policy/abc"))
+
+	mock.AssertExpectationsForObjects(t, &dl)
+}
+
+func TestCacheRetriesFailedDownloads(t *testing.T) {
+	dl := mockDownloader{}
+	dl.On("Download", mock.Anything, "https://example.com/user/foo.git", false).Return(errors.New("expected")).Once()
+	dl.On("Download", mock.Anything, "https://example.com/user/foo.git", false).Return(nil).Once()
+	ctx := usingDownloader(utils.WithFS(context.Background(), afero.NewMemMapFs()), &dl)
+
+	c, err := NewCache(ctx, "")
+	require.NoError(t, err)
+	defer c.Cleanup()
+
+	ctx = WithCache(ctx, c)
+	p := PolicyUrl{Url: "https://example.com/user/foo.git", Kind: "policy"}
+
+	_, err = p.GetPolicy(ctx, "/tmp/ec-work-1234", false)
+	assert.EqualError(t, err, "expected")
+
+	dir, err := p.GetPolicy(ctx, "/tmp/ec-work-1234", false)
+	assert.NoError(t, err)
+	assert.True(t, IsShared(ctx, dir))
+
+	mock.AssertExpectationsForObjects(t, &dl)
+}
+
+type contextRecordingDownloader struct {
+	err error
+}
+
+func (d *contextRecordingDownloader) Download(ctx context.Context, _ string, _ string, _ bool) error {
+	d.err = ctx.Err()
+	return d.err
+}
+
+func TestCacheDownloadsWithRunContext(t *testing.T) {
+	dl := contextRecordingDownloader{}
+	ctx := context.WithValue(utils.WithFS(context.Background(), afero.NewMemMapFs()), DownloaderFuncKey, &dl)
+
+	c, err := NewCache(ctx, "")
+	require.NoError(t, err)
+	defer c.Cleanup()
+
+	// the context of a single evaluation, e.g. one that timed out, does not
+	// affect the download shared with other evaluations
+	evaluationCtx, cancel := context.WithCancel(WithCache(ctx, c))
+	cancel()
+
+	p := PolicyUrl{Url: "https://example.com/user/foo.git", Kind: "policy"}
+	_, err = p.GetPolicy(evaluationCtx, "/tmp/ec-work-1234", false)
+	assert.NoError(t, err)
+	assert.NoError(t, dl.err)
+}
+
+func TestCachePersistsPinnedSources(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	ctx := utils.WithFS(context.Background(), fs)
+
+	dl := mockDownloader{}
+	dl.On("Download", mock.Anything, pinnedUrl, false).Run(func(args mock.Arguments) {
+		assert.Contains(t, args.String(0), ".download-")
+		require.NoError(t, fs.MkdirAll(args.String(0), 0o755))
+	}).Return(nil).Once()
+	dl.On("Download", mock.Anything, "https://example.com/user/foo.git", false).Return(nil).Twice()
+	ctx = usingDownloader(ctx, &dl)
+
+	pinned := PolicyUrl{Url: pinnedUrl, Kind: "policy"}
+	unpinned := PolicyUrl{Url: "https://example.com/user/foo.git", Kind: "data"}
+
+	var persisted string
+	for run := 0; run < 2; run++ {
+		c, err := NewCache(ctx, "/cache")
+		require.NoError(t, err)
+
+		runCtx := WithCache(ctx, c)
+		dir, err := pinned.GetPolicy(runCtx, "/tmp/ec-work-1234", false)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(dir, "/cache/policy/"))
+		if run > 0 {
+			assert.Equal(t, persisted, dir)
+		}
+		persisted = dir
+
+		dir, err = unpinned.GetPolicy(runCtx, "/tmp/ec-work-1234", false)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(dir, c.runDir+"/data/"))
+
+		c.Cleanup()
+	}
+
+	mock.AssertExpectationsForObjects(t, &dl)
+}
+
+func TestCacheReplacesIncompleteEntries(t *testing.T) {
+	fs := afero.NewOsFs()
+	ctx := utils.WithFS(context.Background(), fs)
+	persistDir := t.TempDir()
+
+	dl := mockDownloader{}
+	dl.On("Download", mock.Anything, pinnedUrl, false).Run(func(args mock.Arguments) {
+		require.NoError(t, fs.MkdirAll(args.String(0), 0o755))
+		require.NoError(t, afero.WriteFile(fs, path.Join(args.String(0), "policy.rego"), []byte("package main"), 0o644))
+	}).Return(nil).Once()
+	ctx = usingDownloader(ctx, &dl)
+
+	c, err := NewCache(ctx, persistDir)
+	require.NoError(t, err)
+	defer c.Cleanup()
+
+	key := fmt.Sprintf("%x", sha256.Sum224([]byte("policy/"+pinnedUrl)))
+	dest := path.Join(persistDir, "policy", key)
+	// left over by an interrupted download
+	require.NoError(t, fs.MkdirAll(dest, 0o755))
+	require.NoError(t, afero.WriteFile(fs, path.Join(dest, "partial"), []byte("partial"), 0o644))
+
+	p := PolicyUrl{Url: pinnedUrl, Kind: "policy"}
+	dir, err := p.GetPolicy(WithCache(ctx, c), "/tmp/ec-work-1234", false)
+	require.NoError(t, err)
+	assert.Equal(t, dest, dir)
+
+	files, err := afero.ReadDir(fs, dest)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "policy.rego", files[0].Name())
+
+	complete, err := afero.Exists(fs, dest+completeSuffix)
+	require.NoError(t, err)
+	assert.True(t, complete)
+
+	// no temporary download directories are left behind
+	entries, err := afero.ReadDir(fs, path.Join(persistDir, "policy"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	mock.AssertExpectationsForObjects(t, &dl)
+}
+
+func TestPinned(t *testing.T) {
+	assert.True(t, pinned.MatchString(pinnedUrl))
+	assert.True(t, pinned.MatchString("git::https://example.com/user/foo.git//policy?ref=0123456789abcdef0123456789abcdef01234567"))
+	assert.False(t, pinned.MatchString("git::https://example.com/user/foo.git//policy?ref=main"))
+	assert.False(t, pinned.MatchString("oci::registry.io/policy:latest"))
+}
@@ -30,6 +30,7 @@ import (
 	"path"
 	"time"
 
+	ecc "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/enterprise-contract/ec-cli/internal/downloader"
@@ -64,22 +65,37 @@ type PolicyUrl struct {
 	Kind policyKind
 }
 
-// GetPolicies clones the repository for a given PolicyUrl
+// GetPolicies clones the repository for a given PolicyUrl. If a Cache has
+// been provided via WithCache the source is downloaded into the Cache, once,
+// using the context of the Cache, and the directory within the Cache is
+// returned instead.
 func (p *PolicyUrl) GetPolicy(ctx context.Context, workDir string, showMsg bool) (string, error) {
 	sourceUrl := p.PolicyUrl()
 
+	if c, ok := ctx.Value(cacheKey).(*Cache); ok && c != nil {
+		return c.fetch(p.Subdir(), sourceUrl, func(ctx context.Context, dest string) error {
+			return p.download(ctx, dest, showMsg)
+		})
+	}
+
 	dest := uniqueDestination(workDir, p.Subdir(), sourceUrl)
 
+	return dest, p.download(ctx, dest, showMsg)
+}
+
+func (p *PolicyUrl) download(ctx context.Context, dest string, showMsg bool) error {
+	sourceUrl := p.PolicyUrl()
+
 	// Checkout policy repo into work directory.
 	log.Debugf("Downloading policy files from source url %s to destination %s", sourceUrl, dest)
 
 	x := ctx.Value(DownloaderFuncKey)
 
 	if dl, ok := x.(downloaderFunc); ok {
-		return dest, dl.Download(ctx, dest, sourceUrl, showMsg)
+		return dl.Download(ctx, dest, sourceUrl, showMsg)
 	}
 
-	return dest, downloader.Download(ctx, dest, sourceUrl, showMsg)
+	return downloader.Download(ctx, dest, sourceUrl, showMsg)
 }
 
 func (p *PolicyUrl) PolicyUrl() string {
@@ -126,3 +142,26 @@ func (s inlineData) PolicyUrl() string {
 func (s inlineData) Subdir() string {
 	return "data"
 }
+
+// FetchPolicySources returns the policy sources of the source group, i.e. the
+// policy and data sources followed by the inline rule data, if any.
+func FetchPolicySources(s ecc.Source) ([]PolicySource, error) {
+	policySources := make([]PolicySource, 0, len(s.Policy)+len(s.Data))
+
+	for _, policySourceUrl := range s.Policy {
+		url := PolicyUrl{Url: policySourceUrl, Kind: PolicyKind}
+		policySources = append(policySources, &url)
+	}
+
+	for _, dataSourceUrl := range s.Data {
+		url := PolicyUrl{Url: dataSourceUrl, Kind: DataKind}
+		policySources = append(policySources, &url)
+	}
+
+	if s.RuleData != nil {
+		data := append(append([]byte(`{"rule_data__configuration__":`), s.RuleData.Raw...), '}')
+		policySources = append(policySources, InlineData(data))
+	}
+
+	return policySources, nil
+}
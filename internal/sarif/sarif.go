@@ -0,0 +1,255 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sarif provides the subset of the Static Analysis Results Interchange
+// Format (SARIF) 2.1.0 needed to report policy violations and warnings.
+package sarif
+
+import (
+	conftestOutput "github.com/open-policy-agent/conftest/output"
+)
+
+const (
+	schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	version = "2.1.0"
+
+	toolName           = "ec"
+	toolInformationURI = "https://enterprisecontract.dev"
+)
+
+// Levels of the results
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+type Run struct {
+	Tool      Tool       `json:"tool"`
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+	Results   []Result   `json:"results"`
+}
+
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+type Driver struct {
+	Name           string `json:"name"`
+	Version        string `json:"version,omitempty"`
+	InformationURI string `json:"informationUri"`
+	Rules          []Rule `json:"rules,omitempty"`
+}
+
+type Rule struct {
+	ID               string   `json:"id"`
+	Name             string   `json:"name,omitempty"`
+	ShortDescription *Message `json:"shortDescription,omitempty"`
+	FullDescription  *Message `json:"fullDescription,omitempty"`
+	Help             *Message `json:"help,omitempty"`
+	HelpURI          string   `json:"helpUri,omitempty"`
+}
+
+type Artifact struct {
+	Location    ArtifactLocation `json:"location"`
+	Description *Message         `json:"description,omitempty"`
+}
+
+type Result struct {
+	RuleID       string        `json:"ruleId,omitempty"`
+	RuleIndex    *int          `json:"ruleIndex,omitempty"`
+	Level        string        `json:"level"`
+	Message      Message       `json:"message"`
+	Locations    []Location    `json:"locations"`
+	Suppressions []Suppression `json:"suppressions,omitempty"`
+}
+
+// Suppression records that a result is covered by a policy exception
+type Suppression struct {
+	Kind          string `json:"kind"`
+	Justification string `json:"justification,omitempty"`
+}
+
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+}
+
+type ArtifactLocation struct {
+	URI   string `json:"uri"`
+	Index *int   `json:"index,omitempty"`
+}
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Builder collects the rules and results of a single run, each rule is
+// recorded once regardless of the number of results referring to it.
+type Builder struct {
+	run       Run
+	rules     map[string]int
+	artifacts map[string]int
+	metadata  map[string]map[string]interface{}
+}
+
+// NewBuilder returns a Builder for a run of the given version of ec.
+func NewBuilder(toolVersion string) *Builder {
+	return &Builder{
+		run: Run{
+			Tool: Tool{
+				Driver: Driver{
+					Name:           toolName,
+					Version:        toolVersion,
+					InformationURI: toolInformationURI,
+				},
+			},
+			Results: []Result{},
+		},
+		rules:     map[string]int{},
+		artifacts: map[string]int{},
+	}
+}
+
+// AddRuleMetadata provides the metadata of the rules, keyed by the rule code,
+// used to describe the rules. Otherwise the rules are described by the
+// metadata of the results, which might have been reduced to just the code.
+func (b *Builder) AddRuleMetadata(rules map[string]map[string]interface{}) {
+	if b.metadata == nil {
+		b.metadata = map[string]map[string]interface{}{}
+	}
+
+	for code, metadata := range rules {
+		if _, ok := b.metadata[code]; !ok {
+			b.metadata[code] = metadata
+		}
+	}
+}
+
+// Add records the results, at the given level, as located in the artifact at
+// the given uri, e.g. the image reference of a component or the file name of
+// a definition. The description, if not empty, describes the artifact.
+// Results covered by a policy exception are recorded as suppressed.
+func (b *Builder) Add(uri, description, level string, results []conftestOutput.Result) {
+	artifact := b.artifact(uri, description)
+
+	for _, r := range results {
+		result := Result{
+			Level:   level,
+			Message: Message{Text: r.Message},
+			Locations: []Location{
+				{
+					PhysicalLocation: PhysicalLocation{
+						ArtifactLocation: ArtifactLocation{URI: uri, Index: &artifact},
+					},
+				},
+			},
+		}
+
+		if code := metadataString(r.Metadata, "code"); code != "" {
+			rule := b.rule(code, r)
+			result.RuleID = code
+			result.RuleIndex = &rule
+		}
+
+		if exception, ok := r.Metadata["exception"].(map[string]interface{}); ok {
+			result.Suppressions = []Suppression{{
+				Kind:          "external",
+				Justification: metadataString(exception, "justification"),
+			}}
+		}
+
+		b.run.Results = append(b.run.Results, result)
+	}
+}
+
+// Log returns the SARIF log holding the run.
+func (b *Builder) Log() Log {
+	return Log{
+		Schema:  schema,
+		Version: version,
+		Runs:    []Run{b.run},
+	}
+}
+
+func (b *Builder) artifact(uri, description string) int {
+	if i, ok := b.artifacts[uri]; ok {
+		return i
+	}
+
+	a := Artifact{Location: ArtifactLocation{URI: uri}}
+	if description != "" {
+		a.Description = &Message{Text: description}
+	}
+
+	i := len(b.run.Artifacts)
+	b.run.Artifacts = append(b.run.Artifacts, a)
+	b.artifacts[uri] = i
+
+	return i
+}
+
+// rule returns the index of the rule with the given code, the rule is added
+// when first seen using the metadata provided via AddRuleMetadata, or else the
+// metadata of the result
+func (b *Builder) rule(code string, r conftestOutput.Result) int {
+	if i, ok := b.rules[code]; ok {
+		return i
+	}
+
+	metadata, ok := b.metadata[code]
+	if !ok {
+		metadata = r.Metadata
+	}
+
+	rule := Rule{
+		ID:      code,
+		HelpURI: metadataString(metadata, "documentation_url"),
+	}
+	if title := metadataString(metadata, "title"); title != "" {
+		rule.Name = title
+		rule.ShortDescription = &Message{Text: title}
+	}
+	if description := metadataString(metadata, "description"); description != "" {
+		rule.FullDescription = &Message{Text: description}
+	}
+	if solution := metadataString(metadata, "solution"); solution != "" {
+		rule.Help = &Message{Text: solution}
+	}
+
+	i := len(b.run.Tool.Driver.Rules)
+	b.run.Tool.Driver.Rules = append(b.run.Tool.Driver.Rules, rule)
+	b.rules[code] = i
+
+	return i
+}
+
+func metadataString(metadata map[string]interface{}, key string) string {
+	if v, ok := metadata[key].(string); ok {
+		return v
+	}
+
+	return ""
+}
@@ -0,0 +1,157 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package sarif
+
+import (
+	"encoding/json"
+	"testing"
+
+	conftestOutput "github.com/open-policy-agent/conftest/output"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder("v1.0.0")
+
+	b.Add("registry.io/repository/image@sha256:abc", "my-component", LevelError, []conftestOutput.Result{
+		{
+			Message: "Missing required task",
+			Metadata: map[string]interface{}{
+				"code":              "tasks.required",
+				"title":             "Required tasks",
+				"description":       "All required tasks must be present",
+				"solution":          "Add the task to the pipeline",
+				"documentation_url": "https://enterprisecontract.dev/docs/ec-policies/release_policy.html#tasks__required",
+			},
+		},
+		{Message: "No code"},
+	})
+	b.Add("registry.io/repository/image@sha256:abc", "my-component", LevelWarning, []conftestOutput.Result{
+		{
+			Message:  "Another missing task",
+			Metadata: map[string]interface{}{"code": "tasks.required"},
+		},
+	})
+	b.Add("registry.io/repository/other@sha256:def", "", LevelError, nil)
+
+	data, err := json.Marshal(b.Log())
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": [{
+			"tool": {
+				"driver": {
+					"name": "ec",
+					"version": "v1.0.0",
+					"informationUri": "https://enterprisecontract.dev",
+					"rules": [{
+						"id": "tasks.required",
+						"name": "Required tasks",
+						"shortDescription": {"text": "Required tasks"},
+						"fullDescription": {"text": "All required tasks must be present"},
+						"help": {"text": "Add the task to the pipeline"},
+						"helpUri": "https://enterprisecontract.dev/docs/ec-policies/release_policy.html#tasks__required"
+					}]
+				}
+			},
+			"artifacts": [
+				{
+					"location": {"uri": "registry.io/repository/image@sha256:abc"},
+					"description": {"text": "my-component"}
+				},
+				{
+					"location": {"uri": "registry.io/repository/other@sha256:def"}
+				}
+			],
+			"results": [
+				{
+					"ruleId": "tasks.required",
+					"ruleIndex": 0,
+					"level": "error",
+					"message": {"text": "Missing required task"},
+					"locations": [{"physicalLocation": {"artifactLocation": {"uri": "registry.io/repository/image@sha256:abc", "index": 0}}}]
+				},
+				{
+					"level": "error",
+					"message": {"text": "No code"},
+					"locations": [{"physicalLocation": {"artifactLocation": {"uri": "registry.io/repository/image@sha256:abc", "index": 0}}}]
+				},
+				{
+					"ruleId": "tasks.required",
+					"ruleIndex": 0,
+					"level": "warning",
+					"message": {"text": "Another missing task"},
+					"locations": [{"physicalLocation": {"artifactLocation": {"uri": "registry.io/repository/image@sha256:abc", "index": 0}}}]
+				}
+			]
+		}]
+	}`, string(data))
+}
+
+func TestBuilderEmpty(t *testing.T) {
+	data, err := json.Marshal(NewBuilder("").Log())
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs": [{
+			"tool": {"driver": {"name": "ec", "informationUri": "https://enterprisecontract.dev"}},
+			"results": []
+		}]
+	}`, string(data))
+}
+
+func TestBuilderRuleMetadata(t *testing.T) {
+	b := NewBuilder("v1.0.0")
+
+	b.AddRuleMetadata(map[string]map[string]interface{}{
+		"tasks.required": {
+			"code":        "tasks.required",
+			"title":       "Required tasks",
+			"description": "All required tasks must be present",
+		},
+	})
+	b.Add("registry.io/repository/image@sha256:abc", "", LevelNote, []conftestOutput.Result{
+		{
+			Message: "Missing required task",
+			Metadata: map[string]interface{}{
+				"code": "tasks.required",
+				"exception": map[string]interface{}{
+					"value":         "tasks.required",
+					"justification": "Migrating to the new task",
+				},
+			},
+		},
+	})
+
+	log := b.Log()
+	rules := log.Runs[0].Tool.Driver.Rules
+	require.Len(t, rules, 1)
+	assert.Equal(t, "Required tasks", rules[0].Name)
+	assert.Equal(t, &Message{Text: "All required tasks must be present"}, rules[0].FullDescription)
+
+	results := log.Runs[0].Results
+	require.Len(t, results, 1)
+	assert.Equal(t, LevelNote, results[0].Level)
+	assert.Equal(t, []Suppression{{Kind: "external", Justification: "Migrating to the new task"}}, results[0].Suppressions)
+}
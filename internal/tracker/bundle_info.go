@@ -28,12 +28,15 @@ import (
 type bundleInfo struct {
 	ref         image.ImageReference
 	collections sets.Set[string] // Set of collection where the bundle should be tracked under.
+	// tasks provided, in the task-bundles collection, or referenced by the
+	// pipelines, in the pipeline-bundles collection, of the bundle
+	tasks map[string][]taskRecord
 }
 
 // newBundleInfo returns information about the bundle, such as which collections it should
-// be added to.
+// be added to, and the tasks it provides or its pipelines reference.
 func newBundleInfo(ctx context.Context, ref image.ImageReference) (*bundleInfo, error) {
-	info := bundleInfo{ref: ref, collections: sets.New[string]()}
+	info := bundleInfo{ref: ref, collections: sets.New[string](), tasks: map[string][]taskRecord{}}
 
 	client := NewClient(ctx)
 	img, err := client.GetImage(ctx, info.ref.Ref())
@@ -46,16 +49,42 @@ func newBundleInfo(ctx context.Context, ref image.ImageReference) (*bundleInfo,
 		return nil, err
 	}
 
+	bundle := info.ref.String()
 	for _, layer := range manifest.Layers {
 		if kind, ok := layer.Annotations[oci.KindAnnotation]; ok {
+			name := layer.Annotations[oci.TitleAnnotation]
 			switch kind {
 			case "pipeline":
 				info.collections.Insert(pipelineCollection)
+
+				obj, err := client.GetTektonObject(ctx, bundle, kind, name)
+				if err != nil {
+					return nil, err
+				}
+				tasks, err := referencedTasks(name, obj)
+				if err != nil {
+					return nil, err
+				}
+				info.tasks[pipelineCollection] = append(info.tasks[pipelineCollection], tasks...)
 			case "task":
 				info.collections.Insert(taskCollection)
+
+				obj, err := client.GetTektonObject(ctx, bundle, kind, name)
+				if err != nil {
+					return nil, err
+				}
+				task, err := providedTask(name, obj)
+				if err != nil {
+					return nil, err
+				}
+				info.tasks[taskCollection] = append(info.tasks[taskCollection], task)
 			}
 		}
 	}
 
+	for collection, tasks := range info.tasks {
+		info.tasks[collection] = uniqueTasks(tasks)
+	}
+
 	return &info, nil
 }
@@ -19,6 +19,7 @@ package tracker
 import (
 	"context"
 
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
@@ -29,6 +30,7 @@ import (
 type Client interface {
 	GetTektonObject(ctx context.Context, bundle, kind, name string) (runtime.Object, error)
 	GetImage(ctx context.Context, ref name.Reference) (v1.Image, error)
+	ListTags(ctx context.Context, repository name.Repository) ([]string, error)
 }
 
 type contextKey string
@@ -56,5 +58,9 @@ func (c exoClient) GetTektonObject(ctx context.Context, bundle, kind, name strin
 }
 
 func (c exoClient) GetImage(ctx context.Context, ref name.Reference) (v1.Image, error) {
-	return remote.Image(ref, remote.WithContext(ctx))
+	return remote.Image(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(authn.DefaultKeychain))
+}
+
+func (c exoClient) ListTags(ctx context.Context, repository name.Repository) ([]string, error) {
+	return remote.List(repository, remote.WithContext(ctx), remote.WithAuthFromKeychain(authn.DefaultKeychain))
 }
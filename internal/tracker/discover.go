@@ -0,0 +1,229 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/enterprise-contract/ec-cli/internal/image"
+)
+
+// This facilitates unit tests.
+var resolveAll = image.ParseAndResolveAll
+
+// discovered is an image found in a repository along with its creation time
+type discovered struct {
+	ref     image.ImageReference
+	created time.Time
+}
+
+// Discover returns the references, including the digest, of the images in the
+// given repositories that are not yet tracked in the input and, if their
+// creation time is known, newer than the latest image of the repository
+// already tracked. Only the images with a tag fully matching tagRegex, if not
+// empty, are considered. The references are ordered from the oldest to the
+// newest image of each repository, images without a creation time first in
+// the order of their tags, so that, once tracked, the newest image comes first.
+func Discover(ctx context.Context, repositories []string, tagRegex string, input []byte) ([]string, error) {
+	var pattern *regexp.Regexp
+	if tagRegex != "" {
+		var err error
+		if pattern, err = regexp.Compile("^(?:" + tagRegex + ")$"); err != nil {
+			return nil, fmt.Errorf("invalid tag regex %q: %w", tagRegex, err)
+		}
+	}
+
+	t, err := newTracker(input)
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	for _, repository := range repositories {
+		discovered, err := t.discover(ctx, repository, pattern)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, d := range discovered {
+			urls = append(urls, d.ref.String())
+		}
+	}
+
+	return urls, nil
+}
+
+// discover returns the images of the repository, with a tag matching the
+// pattern, not yet in the tracker and, when the creation times are known,
+// created after the latest image of the repository in the tracker
+func (t Tracker) discover(ctx context.Context, repository string, pattern *regexp.Regexp) ([]discovered, error) {
+	repo, err := name.NewRepository(repository, name.StrictValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	client := NewClient(ctx)
+	tags, err := client.ListTags(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if pattern == nil || pattern.MatchString(tag) {
+			urls = append(urls, repo.Tag(tag).String())
+		}
+	}
+	log.Debugf("Found %d matching tags in %s", len(urls), repository)
+
+	refs, err := resolveAll(urls, name.StrictValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	// the same image may have multiple tags, e.g. 1.0 and latest, each image
+	// is discovered once, with its preferred tag, at the position of its
+	// first tag
+	preferred := map[string]image.ImageReference{}
+	for _, ref := range refs {
+		if p, ok := preferred[ref.Digest]; !ok || preferTag(ref.Tag, p.Tag) {
+			preferred[ref.Digest] = ref
+		}
+	}
+
+	tracked, latest := t.trackedDigests(repo.Name())
+
+	// The creation time of an image is optional, e.g. tkn bundle push and
+	// reproducible builds leave it unset, and the latest tracked image may no
+	// longer exist in the repository. In both cases there is no cutoff and
+	// every image not yet tracked is discovered.
+	var since time.Time
+	if latest != "" {
+		if since, err = created(ctx, client, repo.Digest(latest)); err != nil {
+			if !isNotFound(err) {
+				return nil, err
+			}
+			log.Debugf("Latest tracked image %s not found in %s, discovering all untracked images", latest, repository)
+		}
+	}
+
+	var found []discovered
+	for _, ref := range refs {
+		if tracked[ref.Digest] {
+			continue
+		}
+		tracked[ref.Digest] = true
+		ref := preferred[ref.Digest]
+
+		c, err := created(ctx, client, ref.Ref())
+		if err != nil {
+			return nil, err
+		}
+
+		if !since.IsZero() && !c.IsZero() && !c.After(since) {
+			log.Debugf("Skipping %s, created before the latest tracked image %s", ref.String(), latest)
+			continue
+		}
+
+		found = append(found, discovered{ref: ref, created: c})
+	}
+
+	// images without a creation time keep the order of their tags
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].created.Before(found[j].created)
+	})
+
+	return found, nil
+}
+
+// preferTag reports whether the tag a is preferred over the tag b of the same
+// image. Any tag is preferred over latest, then the most specific tag, i.e.
+// the one with the most parts, e.g. 1.2.3 over 1.2, then the longest and
+// finally the lexically smallest one.
+func preferTag(a, b string) bool {
+	if (a == "latest") != (b == "latest") {
+		return b == "latest"
+	}
+
+	parts := func(tag string) int {
+		return len(strings.FieldsFunc(tag, func(r rune) bool {
+			return r == '.' || r == '-' || r == '_'
+		}))
+	}
+	if pa, pb := parts(a), parts(b); pa != pb {
+		return pa > pb
+	}
+
+	if len(a) != len(b) {
+		return len(a) > len(b)
+	}
+
+	return a < b
+}
+
+// trackedDigests returns the digests of the tracked images of the repository,
+// from all collections, and the digest of the latest one, i.e. the one with
+// the most recent effective_on date
+func (t Tracker) trackedDigests(repository string) (map[string]bool, string) {
+	digests := map[string]bool{}
+	var latest string
+	var latestOn time.Time
+	for _, collection := range t.collections {
+		for _, r := range collection[repository] {
+			digests[r.Digest] = true
+			if latest == "" || r.EffectiveOn.After(latestOn) {
+				latest = r.Digest
+				latestOn = r.EffectiveOn
+			}
+		}
+	}
+
+	return digests, latest
+}
+
+// created returns the creation time of the image
+func created(ctx context.Context, client Client, ref name.Reference) (time.Time, error) {
+	img, err := client.GetImage(ctx, ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	config, err := img.ConfigFile()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return config.Created.Time, nil
+}
+
+// isNotFound returns true if the error is the registry reporting that the
+// image does not exist, e.g. because it was garbage collected
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,243 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package tracker
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	hd "github.com/MakeNowJust/heredoc"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/image"
+)
+
+func mustCreateImage(t *testing.T, created time.Time) v1.Image {
+	img, err := random.Image(0, 0)
+	require.NoError(t, err)
+
+	img, err = mutate.CreatedAt(img, v1.Time{Time: created})
+	require.NoError(t, err)
+
+	return img
+}
+
+func mockResolveAll(t *testing.T, digests map[string]v1.Hash) {
+	resolveAll = func(urls []string, opts ...name.Option) ([]image.ImageReference, error) {
+		refs := make([]image.ImageReference, 0, len(urls))
+		for _, url := range urls {
+			tag := url[len("registry.com/repo:"):]
+			ref, err := image.NewImageReference(url+"@"+digests[tag].String(), opts...)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, *ref)
+		}
+		return refs, nil
+	}
+	t.Cleanup(func() {
+		resolveAll = image.ParseAndResolveAll
+	})
+}
+
+func TestDiscover(t *testing.T) {
+	mockResolveAll(t, map[string]v1.Hash{
+		"0.1": sampleHashOne,
+		"0.2": sampleHashTwo,
+		"0.3": sampleHashThree,
+		// same image as 0.3
+		"latest": sampleHashThree,
+	})
+
+	day := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	input := []byte(hd.Doc(`
+		---
+		pipeline-bundles:
+		  registry.com/repo:
+		    - digest: ` + sampleHashOne.String() + `
+		      effective_on: "2023-06-01T00:00:00Z"
+		      tag: "0.1"
+	`))
+
+	cases := []struct {
+		name     string
+		images   map[string]v1.Image
+		input    []byte
+		expected []string
+	}{
+		{
+			name: "newer than the latest tracked",
+			images: map[string]v1.Image{
+				"registry.com/repo@" + sampleHashOne.String():       mustCreateImage(t, day.Add(24*time.Hour)),
+				"registry.com/repo:0.2@" + sampleHashTwo.String():   mustCreateImage(t, day),
+				"registry.com/repo:0.3@" + sampleHashThree.String(): mustCreateImage(t, day.Add(48*time.Hour)),
+			},
+			input: input,
+			expected: []string{
+				"registry.com/repo:0.3@" + sampleHashThree.String(),
+			},
+		},
+		{
+			name: "ordered by creation time",
+			images: map[string]v1.Image{
+				"registry.com/repo@" + sampleHashOne.String():       mustCreateImage(t, day),
+				"registry.com/repo:0.2@" + sampleHashTwo.String():   mustCreateImage(t, day.Add(48*time.Hour)),
+				"registry.com/repo:0.3@" + sampleHashThree.String(): mustCreateImage(t, day.Add(24*time.Hour)),
+			},
+			input: input,
+			expected: []string{
+				"registry.com/repo:0.3@" + sampleHashThree.String(),
+				"registry.com/repo:0.2@" + sampleHashTwo.String(),
+			},
+		},
+		{
+			name: "without creation time",
+			images: map[string]v1.Image{
+				"registry.com/repo@" + sampleHashOne.String():       mustCreateImage(t, time.Time{}),
+				"registry.com/repo:0.2@" + sampleHashTwo.String():   mustCreateImage(t, time.Time{}),
+				"registry.com/repo:0.3@" + sampleHashThree.String(): mustCreateImage(t, time.Time{}),
+			},
+			input: input,
+			expected: []string{
+				"registry.com/repo:0.2@" + sampleHashTwo.String(),
+				"registry.com/repo:0.3@" + sampleHashThree.String(),
+			},
+		},
+		{
+			name: "latest tracked no longer in the repository",
+			images: map[string]v1.Image{
+				"registry.com/repo:0.2@" + sampleHashTwo.String():   mustCreateImage(t, day),
+				"registry.com/repo:0.3@" + sampleHashThree.String(): mustCreateImage(t, day.Add(24*time.Hour)),
+			},
+			input: input,
+			expected: []string{
+				"registry.com/repo:0.2@" + sampleHashTwo.String(),
+				"registry.com/repo:0.3@" + sampleHashThree.String(),
+			},
+		},
+		{
+			name: "nothing tracked",
+			images: map[string]v1.Image{
+				"registry.com/repo:0.1@" + sampleHashOne.String():   mustCreateImage(t, day),
+				"registry.com/repo:0.2@" + sampleHashTwo.String():   mustCreateImage(t, time.Time{}),
+				"registry.com/repo:0.3@" + sampleHashThree.String(): mustCreateImage(t, day.Add(24*time.Hour)),
+			},
+			expected: []string{
+				"registry.com/repo:0.2@" + sampleHashTwo.String(),
+				"registry.com/repo:0.1@" + sampleHashOne.String(),
+				"registry.com/repo:0.3@" + sampleHashThree.String(),
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx := WithClient(context.Background(), fakeClient{
+				tags: map[string][]string{
+					"registry.com/repo": {"0.1", "0.2", "0.3", "latest", "dev"},
+				},
+				images: c.images,
+			})
+
+			urls, err := Discover(ctx, []string{"registry.com/repo"}, `\d+\.\d+|latest`, c.input)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, urls)
+		})
+	}
+}
+
+func TestDiscoverPreferredTag(t *testing.T) {
+	mockResolveAll(t, map[string]v1.Hash{
+		"latest": sampleHashOne,
+		"1":      sampleHashOne,
+		"1.0":    sampleHashOne,
+		"1.0.0":  sampleHashOne,
+		"1.0.1":  sampleHashOne,
+	})
+
+	expected := "registry.com/repo:1.0.0@" + sampleHashOne.String()
+
+	cases := [][]string{
+		{"latest", "1", "1.0", "1.0.0", "1.0.1"},
+		{"1.0.1", "1.0", "latest", "1.0.0", "1"},
+		{"1", "1.0.0", "1.0.1", "1.0", "latest"},
+	}
+
+	for _, tags := range cases {
+		t.Run(strings.Join(tags, ","), func(t *testing.T) {
+			ctx := WithClient(context.Background(), fakeClient{
+				tags: map[string][]string{
+					"registry.com/repo": tags,
+				},
+				images: map[string]v1.Image{
+					expected: mustCreateImage(t, time.Time{}),
+				},
+			})
+
+			urls, err := Discover(ctx, []string{"registry.com/repo"}, "", nil)
+			assert.NoError(t, err)
+			assert.Equal(t, []string{expected}, urls)
+		})
+	}
+}
+
+func TestPreferTag(t *testing.T) {
+	assert.True(t, preferTag("dev", "latest"))
+	assert.False(t, preferTag("latest", "dev"))
+	assert.True(t, preferTag("1.2.3", "1.2"))
+	assert.True(t, preferTag("v1.2-rc", "1.2.3"))
+	assert.True(t, preferTag("10.2", "1.2"))
+	assert.True(t, preferTag("1.2", "1.3"))
+	assert.False(t, preferTag("1.2", "1.2"))
+}
+
+func TestDiscoverInvalidTagRegex(t *testing.T) {
+	_, err := Discover(context.Background(), []string{"registry.com/repo"}, "(", nil)
+	assert.ErrorContains(t, err, `invalid tag regex "("`)
+}
+
+func TestTrackedDigests(t *testing.T) {
+	older := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(24 * time.Hour)
+
+	tr := Tracker{collections: map[string]map[string][]bundleRecord{
+		pipelineCollection: {
+			"registry.com/repo": {{Digest: "sha256:a", EffectiveOn: older}},
+		},
+		taskCollection: {
+			"registry.com/repo":  {{Digest: "sha256:b", EffectiveOn: newer}},
+			"registry.com/other": {{Digest: "sha256:c", EffectiveOn: newer.Add(time.Hour)}},
+		},
+	}}
+
+	digests, latest := tr.trackedDigests("registry.com/repo")
+	assert.Equal(t, map[string]bool{"sha256:a": true, "sha256:b": true}, digests)
+	assert.Equal(t, "sha256:b", latest)
+
+	digests, latest = tr.trackedDigests("registry.com/none")
+	assert.Empty(t, digests)
+	assert.Empty(t, latest)
+}
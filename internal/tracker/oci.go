@@ -18,6 +18,7 @@ package tracker
 
 import (
 	"context"
+	"fmt"
 	"io"
 
 	"github.com/google/go-containerregistry/pkg/authn"
@@ -28,6 +29,8 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/google/go-containerregistry/pkg/v1/static"
 	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	log "github.com/sirupsen/logrus"
 )
 
 const (
@@ -58,7 +61,11 @@ func (containerRegistry) read(ref name.Reference, options ...remote.Option) (v1.
 
 var defaultRegistry = containerRegistry{}
 
-func PullImage(ctx context.Context, imageRef string) ([]byte, error) {
+// PullImage returns the tracking data held by the image with the given
+// reference. The signature of the image is verified with checkOpts before the
+// data is read. A nil checkOpts skips the verification and must only be passed
+// when explicitly requested, e.g. via --insecure-skip-verify.
+func PullImage(ctx context.Context, imageRef string, checkOpts *cosign.CheckOpts) ([]byte, error) {
 	ref, err := name.ParseReference(imageRef)
 	if err != nil {
 		return nil, err
@@ -69,6 +76,21 @@ func PullImage(ctx context.Context, imageRef string) ([]byte, error) {
 		return nil, err
 	}
 
+	// The signature is verified for the digest of the image that was read, so
+	// that the data read is the data signed even if the tag has since moved
+	if checkOpts != nil {
+		digest, err := img.Digest()
+		if err != nil {
+			return nil, err
+		}
+
+		if err := verifyImage(ctx, ref.Context().Digest(digest.String()), checkOpts); err != nil {
+			return nil, fmt.Errorf("unable to verify the signature of %s: %w", imageRef, err)
+		}
+	} else {
+		log.Warnf("The signature of %s is not verified, the tracking data is used as is", imageRef)
+	}
+
 	layers, err := img.Layers()
 	if err != nil {
 		return nil, err
@@ -106,7 +128,10 @@ func PullImage(ctx context.Context, imageRef string) ([]byte, error) {
 	return data, nil
 }
 
-func PushImage(ctx context.Context, imageRef string, data []byte, invocation string) (err error) {
+// PushImage pushes the tracking data as an OPA data image with the given
+// reference. The image is signed, once pushed, if signing is enabled in the
+// given options.
+func PushImage(ctx context.Context, imageRef string, data []byte, invocation string, signing SignOptions) (err error) {
 	var ref name.Reference
 	ref, err = name.ParseReference(imageRef)
 	if err != nil {
@@ -128,7 +153,24 @@ func PushImage(ctx context.Context, imageRef string, data []byte, invocation str
 		return
 	}
 
-	return r(ctx).write(ref, bundle, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err = r(ctx).write(ref, bundle, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
+		return
+	}
+
+	if !signing.Enabled() {
+		return
+	}
+
+	var digest v1.Hash
+	if digest, err = bundle.Digest(); err != nil {
+		return
+	}
+
+	if err = signImage(ctx, ref.Context().Digest(digest.String()), signing); err != nil {
+		err = fmt.Errorf("unable to sign %s: %w", imageRef, err)
+	}
+
+	return
 }
 
 func r(ctx context.Context) registry {
@@ -71,7 +71,7 @@ func TestPushImage(t *testing.T) {
 
 	ctx := context.WithValue(context.Background(), registryKey, &registry)
 
-	err := PushImage(ctx, imageRef, yaml, invocation)
+	err := PushImage(ctx, imageRef, yaml, invocation, SignOptions{})
 	assert.NoError(t, err)
 
 	registry.AssertExpectations(t)
@@ -132,7 +132,7 @@ func TestPullImage(t *testing.T) {
 
 	ctx := context.WithValue(context.Background(), registryKey, &registry)
 
-	got, err := PullImage(ctx, imageRef)
+	got, err := PullImage(ctx, imageRef, nil)
 	assert.NoError(t, err)
 
 	assert.Equal(t, yaml, got)
@@ -0,0 +1,195 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/fulcio"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/rekor"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/cosign/bundle"
+	cremote "github.com/sigstore/cosign/v2/pkg/cosign/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	cosignSig "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	sigstoreOpts "github.com/sigstore/sigstore/pkg/signature/options"
+	"github.com/sigstore/sigstore/pkg/signature/payload"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/enterprise-contract/ec-cli/internal/utils/oci"
+)
+
+// SignOptions configure the signing of the tracking data image. The image is
+// signed with the key at KeyRef, i.e. a file path or a reference supported by
+// cosign, e.g. k8s://namespace/name. When Keyless is set, the image is signed
+// with an ephemeral key certified by Fulcio for the identity of the OIDC
+// IDToken instead. If RekorURL is set the signature is recorded in the
+// transparency log, which is required to verify keyless signatures.
+type SignOptions struct {
+	KeyRef    string
+	Keyless   bool
+	FulcioURL string
+	RekorURL  string
+	IDToken   string
+}
+
+// Enabled returns whether the tracking data image is to be signed.
+func (o SignOptions) Enabled() bool {
+	return o.KeyRef != "" || o.Keyless
+}
+
+// signer returns the signer and, for keyless signing, the PEM encoded
+// certificate and chain of the ephemeral key
+func signer(ctx context.Context, o SignOptions) (sv signature.SignerVerifier, cert, chain []byte, err error) {
+	if !o.Keyless {
+		sv, err = cosignSig.SignerVerifierFromKeyRef(ctx, o.KeyRef, oci.PassFunc)
+		return
+	}
+
+	key, err := cosign.GeneratePrivateKey()
+	if err != nil {
+		return
+	}
+
+	if sv, err = signature.LoadECDSASignerVerifier(key, crypto.SHA256); err != nil {
+		return
+	}
+
+	f, err := fulcio.NewSigner(ctx, options.KeyOpts{
+		FulcioURL:        o.FulcioURL,
+		IDToken:          o.IDToken,
+		OIDCIssuer:       options.DefaultOIDCIssuerURL,
+		OIDCClientID:     "sigstore",
+		SkipConfirmation: true,
+	}, sv)
+	if err != nil {
+		return
+	}
+
+	return f, f.Cert, f.Chain, nil
+}
+
+// signImage signs the image with the given digest reference, as cosign sign
+// does, and pushes the signature to the registry next to the image. The cosign
+// sign command package is not used as it registers all of the cosign OIDC
+// providers, pulling in their dependencies, e.g. go-spiffe. The building
+// blocks it relies on, the payload, TLog upload, bundle and attach functions
+// from the public cosign packages, are used instead.
+func signImage(ctx context.Context, ref name.Digest, o SignOptions) error {
+	log.Debugf("Signing tracking data image %s", ref)
+
+	sv, cert, chain, err := signer(ctx, o)
+	if err != nil {
+		return err
+	}
+
+	data, err := (&payload.Cosign{Image: ref}).MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	sig, err := sv.SignMessage(bytes.NewReader(data), sigstoreOpts.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	var sigOpts []static.Option
+	if cert != nil {
+		sigOpts = append(sigOpts, static.WithCertChain(cert, chain))
+	}
+
+	if o.RekorURL != "" {
+		// the certificate, or the public key when signed with a key, is
+		// recorded in the transparency log along with the signature
+		recorded := cert
+		if recorded == nil {
+			pub, err := sv.PublicKey()
+			if err != nil {
+				return err
+			}
+			if recorded, err = cryptoutils.MarshalPublicKeyToPEM(pub); err != nil {
+				return err
+			}
+		}
+
+		client, err := rekor.NewClient(o.RekorURL)
+		if err != nil {
+			return err
+		}
+
+		checksum := sha256.New()
+		if _, err := checksum.Write(data); err != nil {
+			return err
+		}
+
+		entry, err := cosign.TLogUpload(ctx, client, sig, checksum, recorded)
+		if err != nil {
+			return err
+		}
+		log.Debugf("Signature of tracking data image %s recorded in the transparency log", ref)
+
+		sigOpts = append(sigOpts, static.WithBundle(bundle.EntryToBundle(entry)))
+	}
+
+	ociSig, err := static.NewSignature(data, base64.StdEncoding.EncodeToString(sig), sigOpts...)
+	if err != nil {
+		return err
+	}
+
+	ociOpts := oci.RegistryClientOptions(ctx)
+
+	se, err := ociremote.SignedEntity(ref, ociOpts...)
+	if err != nil {
+		return err
+	}
+
+	// same as cosign sign, an identical signature already attached is not
+	// attached again
+	dupes := mutate.WithDupeDetector(cremote.NewDupeDetector(sv))
+	if se, err = mutate.AttachSignatureToEntity(se, ociSig, dupes); err != nil {
+		return err
+	}
+
+	return ociremote.WriteSignatures(ref.Repository, se, ociOpts...)
+}
+
+// verifyImage verifies the signature of the image with the given digest
+// reference using the given options, e.g. as returned by Policy.CheckOpts.
+func verifyImage(ctx context.Context, ref name.Digest, checkOpts *cosign.CheckOpts) error {
+	// Set the ClaimVerifier on a shallow *copy* of CheckOpts to avoid
+	// unexpected side-effects
+	co := oci.WithContext(ctx, checkOpts)
+	co.ClaimVerifier = cosign.SimpleClaimVerifier
+
+	if _, _, err := cosign.VerifyImageSignatures(ctx, ref, co); err != nil {
+		return err
+	}
+
+	log.Debugf("Verified the signature of tracking data image %s", ref)
+
+	return nil
+}
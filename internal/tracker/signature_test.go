@@ -0,0 +1,120 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package tracker
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	cosignSig "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func keyPair(t *testing.T) (privateKey string, publicKey []byte) {
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return nil, nil })
+	require.NoError(t, err)
+
+	privateKey = path.Join(t.TempDir(), "cosign.key")
+	require.NoError(t, os.WriteFile(privateKey, keys.PrivateBytes, 0o600))
+
+	return privateKey, keys.PublicBytes
+}
+
+func testRegistry(t *testing.T) string {
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	return u.Host
+}
+
+func checkOpts(t *testing.T, publicKey []byte) *cosign.CheckOpts {
+	verifier, err := cosignSig.LoadPublicKeyRaw(publicKey, crypto.SHA256)
+	require.NoError(t, err)
+
+	return &cosign.CheckOpts{SigVerifier: verifier, IgnoreTlog: true}
+}
+
+func TestSignedImage(t *testing.T) {
+	ctx := context.Background()
+	host := testRegistry(t)
+	privateKey, publicKey := keyPair(t)
+
+	imageRef := fmt.Sprintf("%s/repository/image:tag", host)
+	data := []byte("data: blah")
+
+	require.NoError(t, PushImage(ctx, imageRef, data, "ec track bundle", SignOptions{KeyRef: privateKey}))
+
+	got, err := PullImage(ctx, imageRef, checkOpts(t, publicKey))
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestUnsignedImage(t *testing.T) {
+	ctx := context.Background()
+	host := testRegistry(t)
+	_, publicKey := keyPair(t)
+
+	imageRef := fmt.Sprintf("%s/repository/image:tag", host)
+	data := []byte("data: blah")
+
+	require.NoError(t, PushImage(ctx, imageRef, data, "ec track bundle", SignOptions{}))
+
+	// unsigned images are trusted only if no verification is requested
+	got, err := PullImage(ctx, imageRef, nil)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+
+	_, err = PullImage(ctx, imageRef, checkOpts(t, publicKey))
+	assert.ErrorContains(t, err, "unable to verify the signature of "+imageRef)
+}
+
+func TestImageSignedWithAnotherKey(t *testing.T) {
+	ctx := context.Background()
+	host := testRegistry(t)
+	privateKey, _ := keyPair(t)
+	_, publicKey := keyPair(t)
+
+	imageRef := fmt.Sprintf("%s/repository/image:tag", host)
+
+	require.NoError(t, PushImage(ctx, imageRef, []byte("data: blah"), "ec track bundle", SignOptions{KeyRef: privateKey}))
+
+	_, err := PullImage(ctx, imageRef, checkOpts(t, publicKey))
+	assert.ErrorContains(t, err, "unable to verify the signature of "+imageRef)
+}
+
+func TestSignWithMissingKey(t *testing.T) {
+	ctx := context.Background()
+	host := testRegistry(t)
+
+	imageRef := fmt.Sprintf("%s/repository/image:tag", host)
+
+	err := PushImage(ctx, imageRef, []byte("data: blah"), "ec track bundle", SignOptions{KeyRef: path.Join(t.TempDir(), "missing.key")})
+	assert.ErrorContains(t, err, "unable to sign "+imageRef)
+}
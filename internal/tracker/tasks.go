@@ -0,0 +1,157 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tracker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// versionLabel holds the version of a Tekton task
+const versionLabel = "app.kubernetes.io/version"
+
+// taskRecord identifies a Tekton task provided by a task bundle, or referenced
+// by a pipeline within a pipeline bundle
+type taskRecord struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	// Bundle is the reference of the bundle providing the task referenced by
+	// a pipeline, if any
+	Bundle string `json:"bundle,omitempty"`
+}
+
+// providedTask returns the record of the task object from a task bundle, the
+// version is taken from the app.kubernetes.io/version label
+func providedTask(name string, obj runtime.Object) (taskRecord, error) {
+	var labels map[string]string
+	switch t := obj.(type) {
+	case *v1beta1.Task:
+		labels = t.GetLabels()
+	case *v1.Task:
+		labels = t.GetLabels()
+	default:
+		return taskRecord{}, fmt.Errorf("unexpected object of type %T for task %q", obj, name)
+	}
+
+	return taskRecord{Name: name, Version: labels[versionLabel]}, nil
+}
+
+// referencedTasks returns the records of the tasks referenced by the pipeline
+// object from a pipeline bundle, including the finally tasks. Tasks embedded
+// in the pipeline are not included.
+func referencedTasks(name string, obj runtime.Object) ([]taskRecord, error) {
+	var tasks []taskRecord
+	switch p := obj.(type) {
+	case *v1beta1.Pipeline:
+		for _, pipelineTasks := range [][]v1beta1.PipelineTask{p.Spec.Tasks, p.Spec.Finally} {
+			for _, pt := range pipelineTasks {
+				if pt.TaskRef == nil {
+					continue
+				}
+				params := map[string]string{}
+				for _, param := range pt.TaskRef.Params {
+					params[param.Name] = param.Value.StringVal
+				}
+				if pt.TaskRef.Bundle != "" {
+					params["bundle"] = pt.TaskRef.Bundle
+				}
+				tasks = appendReferencedTask(tasks, pt.TaskRef.Name, params)
+			}
+		}
+	case *v1.Pipeline:
+		for _, pipelineTasks := range [][]v1.PipelineTask{p.Spec.Tasks, p.Spec.Finally} {
+			for _, pt := range pipelineTasks {
+				if pt.TaskRef == nil {
+					continue
+				}
+				params := map[string]string{}
+				for _, param := range pt.TaskRef.Params {
+					params[param.Name] = param.Value.StringVal
+				}
+				tasks = appendReferencedTask(tasks, pt.TaskRef.Name, params)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unexpected object of type %T for pipeline %q", obj, name)
+	}
+
+	return tasks, nil
+}
+
+// appendReferencedTask appends the record of the task referenced either by
+// name or through the name, version and bundle parameters of a resolver, e.g.
+// the bundles resolver. Without an explicit version the tag of the bundle, if
+// any, is used as the version.
+func appendReferencedTask(tasks []taskRecord, name string, params map[string]string) []taskRecord {
+	task := taskRecord{
+		Name:    name,
+		Version: params["version"],
+		Bundle:  params["bundle"],
+	}
+	if task.Name == "" {
+		task.Name = params["name"]
+	}
+	if task.Name == "" {
+		// e.g. a task resolved from a git repository
+		return tasks
+	}
+
+	if task.Version == "" && task.Bundle != "" {
+		task.Version = bundleTag(task.Bundle)
+	}
+
+	return append(tasks, task)
+}
+
+// bundleTag returns the tag of the bundle reference, if any, e.g. 0.1 for
+// registry.io/repository/task:0.1@sha256:...
+func bundleTag(bundle string) string {
+	repository, _, _ := strings.Cut(bundle, "@")
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		return repository[i+1:]
+	}
+
+	return ""
+}
+
+// uniqueTasks returns the sorted task records without duplicates
+func uniqueTasks(tasks []taskRecord) []taskRecord {
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].Name != tasks[j].Name {
+			return tasks[i].Name < tasks[j].Name
+		}
+		if tasks[i].Version != tasks[j].Version {
+			return tasks[i].Version < tasks[j].Version
+		}
+		return tasks[i].Bundle < tasks[j].Bundle
+	})
+
+	unique := make([]taskRecord, 0, len(tasks))
+	for i, t := range tasks {
+		if i > 0 && t == tasks[i-1] {
+			continue
+		}
+		unique = append(unique, t)
+	}
+
+	return unique
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package tracker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+)
+
+func TestReferencedTasks(t *testing.T) {
+	bundleParams := func(params map[string]string) v1beta1.ResolverRef {
+		ref := v1beta1.ResolverRef{Resolver: "bundles"}
+		for name, value := range params {
+			ref.Params = append(ref.Params, v1beta1.Param{Name: name, Value: v1beta1.ParamValue{StringVal: value}})
+		}
+		return ref
+	}
+
+	pipeline := v1beta1.Pipeline{}
+	pipeline.Spec.Tasks = []v1beta1.PipelineTask{
+		{TaskRef: &v1beta1.TaskRef{Name: "git-clone"}},
+		{TaskRef: &v1beta1.TaskRef{ResolverRef: bundleParams(map[string]string{
+			"name":   "buildah",
+			"bundle": "registry.io/tasks/buildah:0.1@sha256:abc",
+		})}},
+		{TaskRef: &v1beta1.TaskRef{ResolverRef: bundleParams(map[string]string{
+			"name":    "sast",
+			"version": "0.2",
+			"bundle":  "registry.io/tasks/sast@sha256:def",
+		})}},
+		{TaskRef: &v1beta1.TaskRef{Name: "deprecated", Bundle: "registry.io/tasks/deprecated:0.3"}},
+		// embedded task
+		{TaskSpec: &v1beta1.EmbeddedTask{}},
+	}
+	pipeline.Spec.Finally = []v1beta1.PipelineTask{
+		{TaskRef: &v1beta1.TaskRef{Name: "git-clone"}},
+	}
+
+	tasks, err := referencedTasks("pipeline", &pipeline)
+	assert.NoError(t, err)
+	assert.Equal(t, []taskRecord{
+		{Name: "buildah", Version: "0.1", Bundle: "registry.io/tasks/buildah:0.1@sha256:abc"},
+		{Name: "deprecated", Version: "0.3", Bundle: "registry.io/tasks/deprecated:0.3"},
+		{Name: "git-clone"},
+		{Name: "sast", Version: "0.2", Bundle: "registry.io/tasks/sast@sha256:def"},
+	}, uniqueTasks(tasks))
+
+	_, err = referencedTasks("pipeline", &v1beta1.Task{})
+	assert.EqualError(t, err, `unexpected object of type *v1beta1.Task for pipeline "pipeline"`)
+}
+
+func TestProvidedTask(t *testing.T) {
+	task, err := providedTask("buildah", mustCreateFakeTaskObject("0.1"))
+	assert.NoError(t, err)
+	assert.Equal(t, taskRecord{Name: "buildah", Version: "0.1"}, task)
+
+	task, err = providedTask("buildah", &v1beta1.Task{})
+	assert.NoError(t, err)
+	assert.Equal(t, taskRecord{Name: "buildah"}, task)
+
+	_, err = providedTask("buildah", &v1beta1.Pipeline{})
+	assert.EqualError(t, err, `unexpected object of type *v1beta1.Pipeline for task "buildah"`)
+}
+
+func TestBundleTag(t *testing.T) {
+	assert.Equal(t, "0.1", bundleTag("registry.io/tasks/buildah:0.1@sha256:abc"))
+	assert.Equal(t, "0.1", bundleTag("localhost:5000/tasks/buildah:0.1"))
+	assert.Equal(t, "", bundleTag("localhost:5000/tasks/buildah@sha256:abc"))
+	assert.Equal(t, "", bundleTag("registry.io/tasks/buildah"))
+}
@@ -19,6 +19,10 @@ package tracker
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/go-containerregistry/pkg/name"
@@ -33,6 +37,11 @@ import (
 const (
 	pipelineCollection = "pipeline-bundles"
 	taskCollection     = "task-bundles"
+
+	day = time.Hour * 24
+
+	// DefaultGracePeriod is the time given to update to a newly tracked bundle
+	DefaultGracePeriod = day * 30
 )
 
 type bundleRecord struct {
@@ -41,52 +50,65 @@ type bundleRecord struct {
 	Tag         string    `json:"tag"`
 	Repository  string    `json:"-"`
 	Collection  string    `json:"-"`
+	// Tasks provided by a task bundle, or referenced by the pipelines of a
+	// pipeline bundle
+	Tasks []taskRecord `json:"tasks,omitempty"`
 }
 
+// Tracker holds the records of each collection, e.g. pipeline-bundles or a
+// user-named collection like base-images, grouped by the repository of the
+// tracked images. Top-level entries that are not collections of records are
+// kept as they are and written back unchanged.
 type Tracker struct {
-	PipelineBundles map[string][]bundleRecord `json:"pipeline-bundles,omitempty"`
-	TaskBundles     map[string][]bundleRecord `json:"task-bundles,omitempty"`
+	collections map[string]map[string][]bundleRecord
+	other       map[string]json.RawMessage
 }
 
 // newTracker returns a new initialized instance of Tracker. If path
 // is "", an empty instance is returned.
 func newTracker(input []byte) (t Tracker, err error) {
-	if input != nil {
-		err = yaml.Unmarshal(input, &t)
-		if err != nil {
-			return
-		}
-	} else {
-		t = Tracker{}
-	}
+	t.collections = map[string]map[string][]bundleRecord{}
+	t.other = map[string]json.RawMessage{}
 
-	t.setDefaults()
-	return
-}
+	if input == nil {
+		return
+	}
 
-// setDefaults initializes the required nested attributes.
-func (t *Tracker) setDefaults() {
-	if t.PipelineBundles == nil {
-		t.PipelineBundles = map[string][]bundleRecord{}
+	var entries map[string]json.RawMessage
+	if err = yaml.Unmarshal(input, &entries); err != nil {
+		return
 	}
-	if t.TaskBundles == nil {
-		t.TaskBundles = map[string][]bundleRecord{}
+
+	for key, value := range entries {
+		var collection map[string][]bundleRecord
+		if err := json.Unmarshal(value, &collection); err != nil {
+			log.Debugf("Keeping %q as is, it is not a collection of records: %v", key, err)
+			t.other[key] = value
+			continue
+		}
+		t.collections[key] = collection
 	}
+
+	return
 }
 
 // addBundleRecord includes the given bundle record to the tracker.
 func (t *Tracker) addBundleRecord(record bundleRecord) {
-	var collection map[string][]bundleRecord
-	switch record.Collection {
-	case pipelineCollection:
-		collection = t.PipelineBundles
-	case taskCollection:
-		collection = t.TaskBundles
-	default:
-		log.Warnf("Ignoring record with unexpected collection: %#v", record)
+	if record.Collection == "" {
+		log.Warnf("Ignoring record without a collection: %#v", record)
 		return
 	}
 
+	if t.collections == nil {
+		t.collections = map[string]map[string][]bundleRecord{}
+	}
+
+	collection, ok := t.collections[record.Collection]
+	if !ok {
+		collection = map[string][]bundleRecord{}
+		t.collections[record.Collection] = collection
+	}
+
 	newRecords := []bundleRecord{record}
 	if _, ok := collection[record.Repository]; !ok {
 		collection[record.Repository] = newRecords
@@ -95,9 +117,21 @@ func (t *Tracker) addBundleRecord(record bundleRecord) {
 	}
 }
 
-// Output serializes the Tracker state as YAML
+// Output serializes the Tracker state as YAML, collections without any records
+// are omitted
 func (t Tracker) Output() ([]byte, error) {
-	out, err := yaml.Marshal(t)
+	entries := map[string]any{}
+	for key, value := range t.other {
+		entries[key] = value
+	}
+
+	for name, collection := range t.collections {
+		if len(collection) > 0 {
+			entries[name] = collection
+		}
+	}
+
+	out, err := yaml.Marshal(entries)
 	if err != nil {
 		return nil, err
 	}
@@ -106,11 +140,31 @@ func (t Tracker) Output() ([]byte, error) {
 	return yamlfmt.Format(bytes.NewBuffer(out), true)
 }
 
+// EffectiveOn holds the effective_on dates of the tracked bundles, Default
+// applies to any bundle without an entry in Overrides, keyed by the url of the
+// bundle.
+type EffectiveOn struct {
+	Default   time.Time
+	Overrides map[string]time.Time
+}
+
+// For returns the effective_on date of the bundle with the given url.
+func (e EffectiveOn) For(url string) time.Time {
+	if effectiveOn, ok := e.Overrides[url]; ok {
+		return effectiveOn
+	}
+
+	return e.Default
+}
+
 // Track implements the common workflow of loading an existing tracker file and adding
 // records to one of its collections.
 // Each url is expected to reference a valid Tekton bundle. Each bundle may be added
 // to none, 1, or 2 collections depending on the Tekton resource types they include.
-func Track(ctx context.Context, urls []string, input []byte, prune bool) ([]byte, error) {
+// If collection is not empty, each url may reference any OCI artifact, e.g. a base
+// image, and is added only to the given collection regardless of its content.
+// The records of each bundle are effective on the date given by effectiveOn.
+func Track(ctx context.Context, urls []string, input []byte, prune bool, effectiveOn EffectiveOn, collection string) ([]byte, error) {
 	refs, err := image.ParseAndResolveAll(urls, name.StrictValidation)
 	if err != nil {
 		return nil, err
@@ -121,23 +175,35 @@ func Track(ctx context.Context, urls []string, input []byte, prune bool) ([]byte
 		return nil, err
 	}
 
-	effective_on := effectiveOn()
-	for _, ref := range refs {
-		info, err := newBundleInfo(ctx, ref)
-		if err != nil {
-			return nil, err
+	// refs are in the same order as the urls
+	for i, ref := range refs {
+		effective_on := effectiveOn.For(urls[i])
+
+		collections := []string{collection}
+		tasks := map[string][]taskRecord{}
+		if collection == "" {
+			info, err := newBundleInfo(ctx, ref)
+			if err != nil {
+				return nil, err
+			}
+
+			collections = sets.List(info.collections)
+			tasks = info.tasks
+			if len(collections) == 0 {
+				log.Warnf("%s contains no Tekton pipelines or tasks, use a collection to track it", urls[i])
+			}
 		}
 
-		for _, collection := range sets.List(info.collections) {
+		for _, c := range collections {
 			t.addBundleRecord(bundleRecord{
 				Digest:      ref.Digest,
 				Tag:         ref.Tag,
 				EffectiveOn: effective_on,
 				Repository:  ref.Repository,
-				Collection:  collection,
+				Collection:  c,
+				Tasks:       tasks[c],
 			})
 		}
-
 	}
 
 	t.filterBundles(prune)
@@ -145,24 +211,46 @@ func Track(ctx context.Context, urls []string, input []byte, prune bool) ([]byte
 	return t.Output()
 }
 
-// effectiveOn returns an RFC3339 representation of the beginning of the
-// closest day 30 days into the future. 30 is a best guess number. In the
-// future, this may have to be configurable.
-func effectiveOn() time.Time {
-	day := time.Hour * 24
-	duration := day * 30
+// effectiveIn returns an RFC3339 representation of the beginning of the
+// closest day the given duration into the future.
+func effectiveIn(duration time.Duration) time.Time {
 	// Round to the 0 time of the day for consistency. Also, zero out nanoseconds
 	// to avoid RFC3339Nano from being used by MarshalJSON.
 	return time.Now().Add(duration).UTC().Round(day)
 }
 
-// filterBundles applies filterRecords to PipelienBundles and TaskBundles.
-func (t *Tracker) filterBundles(prune bool) {
-	for ref, records := range t.PipelineBundles {
-		t.PipelineBundles[ref] = filterRecords(records, prune)
+// ParseEffectiveOn returns the effective_on date given either as a duration
+// from today, in days, e.g. 7d, or as a Go duration, e.g. 168h, or as an
+// absolute date, in RFC3339 format, e.g. 2023-06-01T00:00:00Z, or as a plain
+// date, e.g. 2023-06-01.
+func ParseEffectiveOn(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
 	}
-	for ref, records := range t.TaskBundles {
-		t.TaskBundles[ref] = filterRecords(records, prune)
+
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t.UTC(), nil
+	}
+
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && days >= 0 {
+			return effectiveIn(day * time.Duration(days)), nil
+		}
+	}
+
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return effectiveIn(duration), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid effective on %q, expecting a duration, e.g. 7d or 168h, or a date, e.g. 2023-06-01 or 2023-06-01T00:00:00Z", value)
+}
+
+// filterBundles applies filterRecords to the records of every collection.
+func (t Tracker) filterBundles(prune bool) {
+	for _, collection := range t.collections {
+		for ref, records := range collection {
+			collection[ref] = filterRecords(records, prune)
+		}
 	}
 }
 
@@ -21,6 +21,7 @@ package tracker
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"testing"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/google/go-containerregistry/pkg/v1/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
@@ -52,7 +54,7 @@ var sampleHashThree = v1.Hash{
 	Hex:       "284e3029cce3ae5ee0b05866100e300046359f53ae4c77fe6b34c05aa7a72cee",
 }
 
-var expectedEffectiveOn = effectiveOn().Format(time.RFC3339)
+var expectedEffectiveOn = effectiveIn(DefaultGracePeriod).Format(time.RFC3339)
 
 var yesterday = time.Now().Add(time.Hour * 24 * -1).UTC().Format(time.RFC3339)
 
@@ -77,9 +79,17 @@ func TestTrack(t *testing.T) {
 				    - digest: ` + sampleHashOne.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: one
+				      tasks:
+				        - name: buildah
+				        - name: git-clone
+				        - name: summary
 				    - digest: ` + sampleHashTwo.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: two
+				      tasks:
+				        - name: buildah
+				        - name: git-clone
+				        - name: summary
 			`),
 		},
 		{
@@ -95,10 +105,18 @@ func TestTrack(t *testing.T) {
 				    - digest: ` + sampleHashOne.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: "1.0"
+				      tasks:
+				        - name: buildah
+				        - name: git-clone
+				        - name: summary
 				  registry.com/two:
 				    - digest: ` + sampleHashTwo.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: "2.0"
+				      tasks:
+				        - name: buildah
+				        - name: git-clone
+				        - name: summary
 			`),
 		},
 		{
@@ -121,6 +139,10 @@ func TestTrack(t *testing.T) {
 				    - digest: ` + sampleHashTwo.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: two
+				      tasks:
+				        - name: buildah
+				        - name: git-clone
+				        - name: summary
 				    - digest: ` + sampleHashOne.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: one
@@ -150,6 +172,10 @@ func TestTrack(t *testing.T) {
 				    - digest: ` + sampleHashTwo.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: "2.0"
+				      tasks:
+				        - name: buildah
+				        - name: git-clone
+				        - name: summary
 			`),
 		},
 		{
@@ -171,6 +197,10 @@ func TestTrack(t *testing.T) {
 				    - digest: ` + sampleHashTwo.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: "2.0"
+				      tasks:
+				        - name: buildah
+				        - name: git-clone
+				        - name: summary
 				task-bundles:
 				  registry.com/one:
 				    - digest: ` + sampleHashOne.String() + `
@@ -190,11 +220,18 @@ func TestTrack(t *testing.T) {
 				    - digest: ` + sampleHashOne.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: "1.0"
+				      tasks:
+				        - name: buildah
+				        - name: git-clone
+				        - name: summary
 				task-bundles:
 				  registry.com/mixed:
 				    - digest: ` + sampleHashOne.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: "1.0"
+				      tasks:
+				        - name: task-v1
+				          version: "0.1"
 			`),
 		},
 		{
@@ -251,6 +288,10 @@ func TestTrack(t *testing.T) {
 				    - digest: ` + sampleHashOne.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: "1.0"
+				      tasks:
+				        - name: buildah
+				        - name: git-clone
+				        - name: summary
 				    - digest: ` + sampleHashThree.String() + `
 				      effective_on: "` + yesterday + `"
 				      tag: "0.3"
@@ -259,6 +300,9 @@ func TestTrack(t *testing.T) {
 				    - digest: ` + sampleHashOne.String() + `
 				      effective_on: "` + expectedEffectiveOn + `"
 				      tag: "1.0"
+				      tasks:
+				        - name: task-v1
+				          version: "0.1"
 				    - digest: ` + sampleHashThree.String() + `
 				      effective_on: "` + yesterday + `"
 				      tag: "0.3"
@@ -273,7 +317,7 @@ func TestTrack(t *testing.T) {
 			client := fakeClient{objects: testObjects, images: testImages}
 			ctx = WithClient(ctx, client)
 
-			output, err := Track(ctx, tt.urls, tt.input, tt.prune)
+			output, err := Track(ctx, tt.urls, tt.input, tt.prune, EffectiveOn{Default: effectiveIn(DefaultGracePeriod)}, "")
 			assert.NoError(t, err)
 			assert.Equal(t, tt.output, string(output))
 		})
@@ -281,9 +325,159 @@ func TestTrack(t *testing.T) {
 
 }
 
+func TestTrackEffectiveOnOverride(t *testing.T) {
+	ctx := WithClient(context.Background(), fakeClient{objects: testObjects, images: testImages})
+
+	one := "registry.com/one:1.0@" + sampleHashOne.String()
+	two := "registry.com/two:2.0@" + sampleHashTwo.String()
+	override := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	output, err := Track(ctx, []string{one, two}, nil, true, EffectiveOn{
+		Default:   effectiveIn(DefaultGracePeriod),
+		Overrides: map[string]time.Time{two: override},
+	}, "")
+	assert.NoError(t, err)
+	assert.Equal(t, hd.Doc(`
+		---
+		pipeline-bundles:
+		  registry.com/one:
+		    - digest: `+sampleHashOne.String()+`
+		      effective_on: "`+expectedEffectiveOn+`"
+		      tag: "1.0"
+		      tasks:
+		        - name: buildah
+		        - name: git-clone
+		        - name: summary
+		  registry.com/two:
+		    - digest: `+sampleHashTwo.String()+`
+		      effective_on: "2099-01-01T00:00:00Z"
+		      tag: "2.0"
+		      tasks:
+		        - name: buildah
+		        - name: git-clone
+		        - name: summary
+	`), string(output))
+}
+
+func TestTrackCollection(t *testing.T) {
+	// the images are not Tekton bundles, and are not inspected
+	ctx := WithClient(context.Background(), fakeClient{})
+
+	input := []byte(hd.Doc(`
+		---
+		base-images:
+		  registry.com/base:
+		    - digest: ` + sampleHashThree.String() + `
+		      effective_on: "` + yesterday + `"
+		      tag: "0.9"
+		pipeline-bundles:
+		  registry.com/one:
+		    - digest: ` + sampleHashThree.String() + `
+		      effective_on: "` + yesterday + `"
+		      tag: "0.9"
+	`))
+
+	output, err := Track(ctx, []string{
+		"registry.com/base:1.0@" + sampleHashOne.String(),
+		"registry.com/builder:1.0@" + sampleHashTwo.String(),
+	}, input, true, EffectiveOn{Default: effectiveIn(DefaultGracePeriod)}, "base-images")
+	assert.NoError(t, err)
+	assert.Equal(t, hd.Doc(`
+		---
+		base-images:
+		  registry.com/base:
+		    - digest: `+sampleHashOne.String()+`
+		      effective_on: "`+expectedEffectiveOn+`"
+		      tag: "1.0"
+		    - digest: `+sampleHashThree.String()+`
+		      effective_on: "`+yesterday+`"
+		      tag: "0.9"
+		  registry.com/builder:
+		    - digest: `+sampleHashTwo.String()+`
+		      effective_on: "`+expectedEffectiveOn+`"
+		      tag: "1.0"
+		pipeline-bundles:
+		  registry.com/one:
+		    - digest: `+sampleHashThree.String()+`
+		      effective_on: "`+yesterday+`"
+		      tag: "0.9"
+	`), string(output))
+}
+
+func TestTrackPreservesUnknownKeys(t *testing.T) {
+	ctx := WithClient(context.Background(), fakeClient{})
+
+	input := []byte(hd.Doc(`
+		---
+		metadata:
+		  owner: team
+		  version: 2
+		notes: kept as is
+	`))
+
+	output, err := Track(ctx, []string{
+		"registry.com/base:1.0@" + sampleHashOne.String(),
+	}, input, true, EffectiveOn{Default: effectiveIn(DefaultGracePeriod)}, "base-images")
+	assert.NoError(t, err)
+	assert.Equal(t, hd.Doc(`
+		---
+		base-images:
+		  registry.com/base:
+		    - digest: `+sampleHashOne.String()+`
+		      effective_on: "`+expectedEffectiveOn+`"
+		      tag: "1.0"
+		metadata:
+		  owner: team
+		  version: 2
+		notes: kept as is
+	`), string(output))
+}
+
+func TestTrackNonTektonArtifact(t *testing.T) {
+	url := "registry.com/base:1.0@" + sampleHashOne.String()
+	ctx := WithClient(context.Background(), fakeClient{images: map[string]v1.Image{
+		url: mustCreateFakeBundleImage(nil),
+	}})
+
+	output, err := Track(ctx, []string{url}, nil, true, EffectiveOn{Default: effectiveIn(DefaultGracePeriod)}, "")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(output), "registry.com/base")
+}
+
+func TestParseEffectiveOn(t *testing.T) {
+	cases := []struct {
+		name     string
+		value    string
+		expected time.Time
+		err      string
+	}{
+		{name: "days", value: "7d", expected: effectiveIn(day * 7)},
+		{name: "zero days", value: "0d", expected: effectiveIn(0)},
+		{name: "duration", value: "168h", expected: effectiveIn(day * 7)},
+		{name: "date", value: "2023-06-01", expected: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "RFC3339", value: "2023-06-01T12:00:00+02:00", expected: time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)},
+		{name: "negative", value: "-7d", err: `invalid effective on "-7d"`},
+		{name: "invalid", value: "soon", err: `invalid effective on "soon"`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			effectiveOn, err := ParseEffectiveOn(c.value)
+			if c.err != "" {
+				assert.ErrorContains(t, err, c.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, effectiveOn)
+		})
+	}
+}
+
 type fakeClient struct {
 	objects map[string]map[string]map[string]runtime.Object
 	images  map[string]v1.Image
+	tags    map[string][]string
 }
 
 func (r fakeClient) GetTektonObject(ctx context.Context, bundle, kind, name string) (runtime.Object, error) {
@@ -301,7 +495,20 @@ func (r fakeClient) GetImage(ctx context.Context, ref name.Reference) (v1.Image,
 	if image, ok := r.images[ref.String()]; ok {
 		return image, nil
 	}
-	return nil, fmt.Errorf("image %q not found", ref)
+	return nil, &transport.Error{
+		StatusCode: http.StatusNotFound,
+		Errors: []transport.Diagnostic{{
+			Code:    transport.ManifestUnknownErrorCode,
+			Message: fmt.Sprintf("image %q not found", ref),
+		}},
+	}
+}
+
+func (r fakeClient) ListTags(ctx context.Context, repository name.Repository) ([]string, error) {
+	if tags, ok := r.tags[repository.Name()]; ok {
+		return tags, nil
+	}
+	return nil, fmt.Errorf("repository %q not found", repository)
 }
 
 var testObjects = map[string]map[string]map[string]runtime.Object{
@@ -329,6 +536,9 @@ var testObjects = map[string]map[string]map[string]runtime.Object{
 		"pipeline": {
 			"pipeline-v1": mustCreateFakePipelineObject(),
 		},
+		"task": {
+			"task-v1": mustCreateFakeTaskObject("0.1"),
+		},
 	},
 }
 
@@ -413,3 +623,10 @@ func mustCreateFakePipelineObject() runtime.Object {
 
 	return &pipeline
 }
+
+func mustCreateFakeTaskObject(version string) runtime.Object {
+	task := v1beta1.Task{}
+	task.SetLabels(map[string]string{versionLabel: version})
+
+	return &task
+}
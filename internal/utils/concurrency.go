@@ -0,0 +1,115 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+// DefaultWorkers is the number of concurrent workers used when none has been
+// configured via WithWorkers.
+const DefaultWorkers = 4
+
+type workersContextKey int
+
+const workersKey workersContextKey = 0
+
+// WithWorkers sets the maximum number of concurrent workers used by
+// ParallelMap.
+func WithWorkers(ctx context.Context, workers int) context.Context {
+	return context.WithValue(ctx, workersKey, workers)
+}
+
+// Workers returns the maximum number of concurrent workers configured via
+// WithWorkers, or DefaultWorkers if none or a non-positive value was set.
+func Workers(ctx context.Context) int {
+	if workers, ok := ctx.Value(workersKey).(int); ok && workers > 0 {
+		return workers
+	}
+
+	return DefaultWorkers
+}
+
+// ParallelMap invokes fn for each of the items using at most Workers(ctx)
+// concurrent goroutines. The results are returned in the same order as the
+// items regardless of the order of completion. If any invocation fails, the
+// context passed to fn is cancelled, the items not yet started are skipped
+// and the first error encountered is returned. Likewise, if ctx is cancelled
+// the remaining items are skipped and the error of ctx is returned. The
+// results of the invocations that did succeed are returned along with the
+// error, leaving the zero value for the others, so that the caller can release
+// any resources they hold.
+func ParallelMap[T any, R any](ctx context.Context, items []T, fn func(context.Context, T) (R, error)) ([]R, error) {
+	results := make([]R, len(items))
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var firstErr error
+	var once sync.Once
+
+	workers := Workers(ctx)
+	if workers > len(items) {
+		workers = len(items)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() != nil {
+					// a previous invocation failed or the parent context was
+					// cancelled, no point in continuing
+					continue
+				}
+
+				r, err := fn(ctx, items[i])
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[i] = r
+			}
+		}()
+	}
+
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return results, firstErr
+	}
+
+	// the items skipped because the parent context was cancelled have no
+	// result
+	if err := parent.Err(); err != nil {
+		return results, err
+	}
+
+	return results, nil
+}
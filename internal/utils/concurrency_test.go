@@ -0,0 +1,135 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkers(t *testing.T) {
+	assert.Equal(t, DefaultWorkers, Workers(context.Background()))
+	assert.Equal(t, DefaultWorkers, Workers(WithWorkers(context.Background(), 0)))
+	assert.Equal(t, 7, Workers(WithWorkers(context.Background(), 7)))
+}
+
+func TestParallelMapOrdering(t *testing.T) {
+	items := []int{5, 4, 3, 2, 1}
+
+	results, err := ParallelMap(WithWorkers(context.Background(), 3), items, func(_ context.Context, i int) (string, error) {
+		// finish in reverse order of submission
+		time.Sleep(time.Duration(i) * time.Millisecond)
+		return fmt.Sprintf("item-%d", i), nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"item-5", "item-4", "item-3", "item-2", "item-1"}, results)
+}
+
+func TestParallelMapBounded(t *testing.T) {
+	var running, max int32
+	items := make([]int, 20)
+
+	_, err := ParallelMap(WithWorkers(context.Background(), 2), items, func(_ context.Context, _ int) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return 0, nil
+	})
+
+	assert.NoError(t, err)
+	assert.LessOrEqual(t, max, int32(2))
+}
+
+func TestParallelMapError(t *testing.T) {
+	var invoked int32
+	results, err := ParallelMap(WithWorkers(context.Background(), 1), []int{1, 2, 3}, func(_ context.Context, i int) (int, error) {
+		atomic.AddInt32(&invoked, 1)
+		if i > 1 {
+			return 0, fmt.Errorf("failed %d", i)
+		}
+		return i, nil
+	})
+
+	assert.EqualError(t, err, "failed 2")
+	// the successful result is kept so it can be released by the caller
+	assert.Equal(t, []int{1, 0, 0}, results)
+	// the item after the failed one is never started
+	assert.Equal(t, int32(2), invoked)
+}
+
+func TestParallelMapCancelsOnError(t *testing.T) {
+	_, err := ParallelMap(WithWorkers(context.Background(), 2), []int{1, 2}, func(ctx context.Context, i int) (int, error) {
+		if i == 1 {
+			return 0, fmt.Errorf("failed %d", i)
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(10 * time.Second):
+			return 0, fmt.Errorf("not cancelled")
+		}
+	})
+
+	assert.EqualError(t, err, "failed 1")
+}
+
+func TestParallelMapCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var invoked int32
+	results, err := ParallelMap(ctx, []int{1, 2, 3}, func(_ context.Context, i int) (*int, error) {
+		atomic.AddInt32(&invoked, 1)
+		return &i, nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int32(0), atomic.LoadInt32(&invoked))
+	assert.Equal(t, []*int{nil, nil, nil}, results)
+}
+
+func TestParallelMapCancelledWhileRunning(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results, err := ParallelMap(WithWorkers(ctx, 1), []int{1, 2, 3}, func(_ context.Context, i int) (*int, error) {
+		// the context is cancelled, e.g. by a timeout, while the first item
+		// is processed
+		cancel()
+		return &i, nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotNil(t, results[0])
+	assert.Nil(t, results[1])
+	assert.Nil(t, results[2])
+}
@@ -21,6 +21,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 	"unicode"
@@ -149,3 +150,30 @@ func HasSuffix(str string, extensions []string) bool {
 func HasJsonOrYamlExt(str string) bool {
 	return HasSuffix(str, []string{".json", ".yaml", ".yml"})
 }
+
+// WalkJsonOrYamlFiles returns the JSON and YAML files within the directory,
+// and its subdirectories, accepted by the filter. The .git directories are
+// skipped.
+func WalkJsonOrYamlFiles(fs afero.Fs, root string, filter func(string) bool) ([]string, error) {
+	var files []string
+	err := afero.Walk(fs, root, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if HasJsonOrYamlExt(fpath) && filter(fpath) {
+			files = append(files, fpath)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
@@ -21,10 +21,12 @@ package utils
 import (
 	"context"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var testJSONPipelineData = `{
@@ -269,3 +271,19 @@ func TestHasJsonOrYamlExt(t *testing.T) {
 		assert.Equal(t, tt.want, HasJsonOrYamlExt(tt.src))
 	}
 }
+
+func TestWalkJsonOrYamlFiles(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	for _, f := range []string{"/root/a.json", "/root/README.md", "/root/nested/b.yaml", "/root/nested/deeper/c.yml", "/root/nested/skip.json", "/root/.git/config.json"} {
+		require.NoError(t, afero.WriteFile(fs, f, []byte(`{}`), 0644))
+	}
+
+	files, err := WalkJsonOrYamlFiles(fs, "/root", func(fpath string) bool {
+		return !strings.HasSuffix(fpath, "skip.json")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/root/a.json", "/root/nested/b.yaml", "/root/nested/deeper/c.yml"}, files)
+
+	_, err = WalkJsonOrYamlFiles(fs, "/missing", func(string) bool { return true })
+	assert.Error(t, err)
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package oci holds the helpers shared by the packages signing, verifying and
+// attaching signatures and attestations of OCI images with cosign.
+package oci
+
+import (
+	"context"
+	"os"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+)
+
+// PassFunc provides the password of the signing key from the
+// COSIGN_PASSWORD environment variable, same as cosign does when not
+// interactive
+func PassFunc(bool) ([]byte, error) {
+	return []byte(os.Getenv("COSIGN_PASSWORD")), nil
+}
+
+// RemoteOptions returns the given options preceded by the ones making the
+// registry requests stop once the context is done and authenticate using the
+// default keychain.
+func RemoteOptions(ctx context.Context, opts ...remote.Option) []remote.Option {
+	return append([]remote.Option{remote.WithContext(ctx), remote.WithAuthFromKeychain(authn.DefaultKeychain)}, opts...)
+}
+
+// RegistryClientOptions returns the given cosign registry options preceded by
+// the RemoteOptions. Cosign doesn't pass the context to the registry client on
+// its own.
+func RegistryClientOptions(ctx context.Context, opts ...ociremote.Option) []ociremote.Option {
+	return append([]ociremote.Option{ociremote.WithRemoteOptions(RemoteOptions(ctx)...)}, opts...)
+}
+
+// WithContext returns a shallow copy of the CheckOpts with the
+// RegistryClientOptions, leaving the given CheckOpts unchanged.
+func WithContext(ctx context.Context, opts *cosign.CheckOpts) *cosign.CheckOpts {
+	o := *opts
+	o.RegistryClientOpts = RegistryClientOptions(ctx, opts.RegistryClientOpts...)
+
+	return &o
+}
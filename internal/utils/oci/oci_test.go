@@ -0,0 +1,63 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package oci
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPassFunc(t *testing.T) {
+	t.Setenv("COSIGN_PASSWORD", "secret")
+
+	password, err := PassFunc(false)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), password)
+}
+
+func TestRemoteOptions(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Len(t, RemoteOptions(ctx), 2)
+	assert.Len(t, RemoteOptions(ctx, remote.WithUserAgent("ec")), 3)
+}
+
+func TestWithContext(t *testing.T) {
+	repo, err := name.NewRepository("registry.io/repository")
+	require.NoError(t, err)
+
+	opts := &cosign.CheckOpts{
+		RegistryClientOpts: []ociremote.Option{ociremote.WithTargetRepository(repo)},
+		IgnoreTlog:         true,
+	}
+
+	withContext := WithContext(context.Background(), opts)
+
+	assert.Len(t, withContext.RegistryClientOpts, 2)
+	assert.True(t, withContext.IgnoreTlog)
+	// the given options are left unchanged
+	assert.Len(t, opts.RegistryClientOpts, 1)
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package vsa
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	ecc "github.com/enterprise-contract/enterprise-contract-controller/api/v1alpha1"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	cosignSig "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+)
+
+type contextKey string
+
+const checkerKey contextKey = "ec.vsa.checker"
+
+// resolvedPolicy is the policy as used to validate an image.
+type resolvedPolicy struct {
+	Spec          ecc.EnterpriseContractPolicySpec `json:"spec"`
+	Exceptions    []policy.Exception               `json:"exceptions,omitempty"`
+	EffectiveTime string                           `json:"effectiveTime,omitempty"`
+}
+
+// PolicyDigest returns the SHA-256 digest, hex encoded, of the JSON
+// representation of the policy as resolved for the component with the given
+// name and image repository. It identifies the policy the image was verified
+// against: the specification with the component overrides applied, the
+// exceptions applying to the image that are active at the effective time, and
+// the effective time as chosen, e.g. "now" or a specific date.
+func PolicyDigest(p policy.Policy, component, imageRepository string) (string, error) {
+	resolved, err := p.ForComponent(component, imageRepository)
+	if err != nil {
+		return "", err
+	}
+
+	rp := resolvedPolicy{
+		Spec:          resolved.Spec(),
+		EffectiveTime: p.ChosenTime(),
+	}
+
+	effective := p.EffectiveTime()
+	for _, e := range p.Exceptions() {
+		if e.AppliesTo(component, imageRepository) && e.IsActive(effective) {
+			rp.Exceptions = append(rp.Exceptions, e)
+		}
+	}
+
+	data, err := json.Marshal(rp)
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(data)
+
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// Checker looks for Verification Summary Attestations, signed by a trusted
+// key, recording that an image passed the validation against a policy.
+type Checker struct {
+	verifier signature.Verifier
+	maxAge   time.Duration
+}
+
+// NewChecker returns a Checker trusting the Verification Summary
+// Attestations signed by the key with the given reference, i.e. a file path or
+// a reference supported by cosign, that were created within the given
+// duration. A zero duration places no limit on the age.
+func NewChecker(ctx context.Context, keyRef string, maxAge time.Duration) (*Checker, error) {
+	verifier, err := cosignSig.PublicKeyFromKeyRef(ctx, keyRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Checker{verifier: verifier, maxAge: maxAge}, nil
+}
+
+// WithChecker returns a context holding the Checker.
+func WithChecker(ctx context.Context, c *Checker) context.Context {
+	return context.WithValue(ctx, checkerKey, c)
+}
+
+// CheckerFrom returns the Checker held in the context, or nil if there is
+// none.
+func CheckerFrom(ctx context.Context) *Checker {
+	if c, ok := ctx.Value(checkerKey).(*Checker); ok {
+		return c
+	}
+
+	return nil
+}
+
+// CheckOpts returns the options to fetch the image attestations signed by the
+// trusted key. The attestations are not expected to be recorded in the
+// transparency log.
+func (c *Checker) CheckOpts() *cosign.CheckOpts {
+	return &cosign.CheckOpts{
+		SigVerifier:   dsse.WrapVerifier(c.verifier),
+		ClaimVerifier: cosign.IntotoSubjectClaimVerifier,
+		IgnoreTlog:    true,
+	}
+}
+
+// Find returns the most recent predicate, among the given verified
+// attestations, recording that the image with the given digest reference
+// passed the validation against the policy with the given digest and that is
+// not older than the maximum age at the given time. Found is false if there is
+// no such predicate.
+func (c *Checker) Find(attestations []oci.Signature, ref name.Digest, policyDigest string, now time.Time) (predicate Predicate, found bool) {
+	for _, att := range attestations {
+		statement, err := decode(att)
+		if err != nil {
+			log.Debugf("Ignoring attestation that is not a Verification Summary Attestation: %v", err)
+			continue
+		}
+
+		p := statement.Predicate
+		switch {
+		case statement.PredicateType != PredicateType:
+			continue
+		case !subjectMatches(statement, ref):
+			log.Debugf("Ignoring Verification Summary Attestation of another image")
+			continue
+		case p.Verifier.ID != VerifierID:
+			log.Debugf("Ignoring Verification Summary Attestation of verifier %q", p.Verifier.ID)
+			continue
+		case p.Policy.Digest["sha256"] != policyDigest:
+			log.Debugf("Ignoring Verification Summary Attestation of another policy")
+			continue
+		case p.VerificationResult != Passed:
+			log.Debugf("Ignoring Verification Summary Attestation with the %s result", p.VerificationResult)
+			continue
+		case c.maxAge > 0 && p.TimeVerified.Add(c.maxAge).Before(now):
+			log.Debugf("Ignoring Verification Summary Attestation created on %s, older than %s", p.TimeVerified, c.maxAge)
+			continue
+		}
+
+		if !found || p.TimeVerified.After(predicate.TimeVerified) {
+			predicate, found = p, true
+		}
+	}
+
+	return
+}
+
+func decode(att oci.Signature) (Statement, error) {
+	var statement Statement
+
+	payload, err := att.Payload()
+	if err != nil {
+		return statement, err
+	}
+
+	var envelope cosign.AttestationPayload
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return statement, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(envelope.PayLoad)
+	if err != nil {
+		return statement, err
+	}
+
+	err = json.Unmarshal(data, &statement)
+
+	return statement, err
+}
+
+func subjectMatches(statement Statement, ref name.Digest) bool {
+	algorithm, hex, _ := strings.Cut(ref.DigestStr(), ":")
+	for _, s := range statement.Subject {
+		if s.Digest[algorithm] == hex {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,216 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package vsa
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	hd "github.com/MakeNowJust/heredoc"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/sigstore/cosign/v2/pkg/oci"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/enterprise-contract/ec-cli/internal/policy"
+)
+
+const policyDigest = "0a7d7d2ba85e5a1b0e2b8f1a0a0b3d7e0ab1c9e1f1f5c3d1e2a7b4c8d9e0f1a2"
+
+func attestation(t *testing.T, statement Statement) oci.Signature {
+	payload, err := json.Marshal(statement)
+	require.NoError(t, err)
+
+	envelope, err := json.Marshal(map[string]any{
+		"payloadType": "application/vnd.in-toto+json",
+		"payload":     base64.StdEncoding.EncodeToString(payload),
+		"signatures":  []any{},
+	})
+	require.NoError(t, err)
+
+	att, err := static.NewAttestation(envelope)
+	require.NoError(t, err)
+
+	return att
+}
+
+func TestPolicyDigest(t *testing.T) {
+	ctx := context.Background()
+	digest := func(policyRef, effectiveTime, component string) string {
+		p, err := policy.NewInputPolicy(ctx, policyRef, effectiveTime)
+		require.NoError(t, err)
+
+		d, err := PolicyDigest(p, component, "registry.io/repository/image")
+		require.NoError(t, err)
+		assert.Len(t, d, 64)
+
+		return d
+	}
+
+	base := digest(`{"publicKey": "k8s://ns/key"}`, "2023-05-01", "web")
+
+	// same policy
+	assert.Equal(t, base, digest(`{"publicKey": "k8s://ns/key"}`, "2023-05-01", "web"))
+	// another specification
+	assert.NotEqual(t, base, digest(`{"publicKey": "k8s://ns/other"}`, "2023-05-01", "web"))
+	// another effective time
+	assert.NotEqual(t, base, digest(`{"publicKey": "k8s://ns/key"}`, "2023-05-02", "web"))
+
+	exception := hd.Doc(`
+		publicKey: k8s://ns/key
+		exceptions:
+		  - value: pkg.rule
+		    components: [web]
+		    expires: "2023-06-01"
+		    justification: known issue
+		    approver: security
+	`)
+	withException := digest(exception, "2023-05-01", "web")
+	assert.NotEqual(t, base, withException)
+	// a changed exception
+	assert.NotEqual(t, withException, digest(strings.Replace(exception, "pkg.rule", "pkg.other", 1), "2023-05-01", "web"))
+	// the exception has expired, same as no exception
+	assert.Equal(t, digest(`{"publicKey": "k8s://ns/key"}`, "2023-07-01", "web"), digest(exception, "2023-07-01", "web"))
+	// the exception does not apply to the component
+	assert.Equal(t, base, digest(exception, "2023-05-01", "db"))
+
+	override := hd.Doc(`
+		publicKey: k8s://ns/key
+		componentOverrides:
+		  - components: [web]
+		    configuration:
+		      include: [pkg]
+	`)
+	assert.NotEqual(t, base, digest(override, "2023-05-01", "web"))
+	// the override does not apply to the component
+	assert.Equal(t, digest(`{"publicKey": "k8s://ns/key"}`, "2023-05-01", "db"), digest(override, "2023-05-01", "db"))
+}
+
+func TestCheckerFromContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, CheckerFrom(ctx))
+
+	c := &Checker{}
+	assert.Same(t, c, CheckerFrom(WithChecker(ctx, c)))
+}
+
+func TestFind(t *testing.T) {
+	now := time.Date(2023, 5, 2, 12, 0, 0, 0, time.UTC)
+	ref := name.MustParseReference("registry.io/repository/image@" + digest).(name.Digest)
+	other := name.MustParseReference("registry.io/repository/image@sha256:0000000000000000000000000000000000000000000000000000000000000000").(name.Digest)
+
+	statement := func(ref name.Digest, modify func(*Predicate)) Statement {
+		p := predicate()
+		p.Policy.Digest = map[string]string{"sha256": policyDigest}
+		p.TimeVerified = now.Add(-time.Hour)
+		if modify != nil {
+			modify(&p)
+		}
+
+		return NewStatement(ref, p)
+	}
+
+	cases := []struct {
+		name         string
+		maxAge       time.Duration
+		statements   []Statement
+		found        bool
+		timeVerified time.Time
+	}{
+		{
+			name:       "none",
+			statements: []Statement{},
+		},
+		{
+			name:         "matching",
+			maxAge:       24 * time.Hour,
+			statements:   []Statement{statement(ref, nil)},
+			found:        true,
+			timeVerified: now.Add(-time.Hour),
+		},
+		{
+			name:   "most recent",
+			maxAge: 24 * time.Hour,
+			statements: []Statement{
+				statement(ref, func(p *Predicate) { p.TimeVerified = now.Add(-3 * time.Hour) }),
+				statement(ref, func(p *Predicate) { p.TimeVerified = now.Add(-2 * time.Hour) }),
+				statement(ref, func(p *Predicate) { p.TimeVerified = now.Add(-4 * time.Hour) }),
+			},
+			found:        true,
+			timeVerified: now.Add(-2 * time.Hour),
+		},
+		{
+			name:       "other image",
+			statements: []Statement{statement(other, nil)},
+		},
+		{
+			name:       "other policy",
+			statements: []Statement{statement(ref, func(p *Predicate) { p.Policy.Digest["sha256"] = "other" })},
+		},
+		{
+			name:       "other verifier",
+			statements: []Statement{statement(ref, func(p *Predicate) { p.Verifier.ID = "https://example.com/verifier" })},
+		},
+		{
+			name:       "failed",
+			statements: []Statement{statement(ref, func(p *Predicate) { p.VerificationResult = Failed })},
+		},
+		{
+			name:       "too old",
+			maxAge:     30 * time.Minute,
+			statements: []Statement{statement(ref, nil)},
+		},
+		{
+			name:         "no max age",
+			statements:   []Statement{statement(ref, func(p *Predicate) { p.TimeVerified = now.Add(-24 * 365 * time.Hour) })},
+			found:        true,
+			timeVerified: now.Add(-24 * 365 * time.Hour),
+		},
+		{
+			name: "other predicate type",
+			statements: []Statement{func() Statement {
+				s := statement(ref, nil)
+				s.PredicateType = "https://slsa.dev/provenance/v0.2"
+				return s
+			}()},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			attestations := make([]oci.Signature, 0, len(c.statements))
+			for _, s := range c.statements {
+				attestations = append(attestations, attestation(t, s))
+			}
+
+			checker := Checker{maxAge: c.maxAge}
+			predicate, found := checker.Find(attestations, ref, policyDigest, now)
+
+			assert.Equal(t, c.found, found)
+			if c.found {
+				assert.Equal(t, c.timeVerified, predicate.TimeVerified)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package vsa creates, signs and attaches SLSA Verification Summary
+// Attestations (https://slsa.dev/spec/v1.0/verification_summary) recording
+// the outcome of validating an image against the Enterprise Contract.
+package vsa
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	cremote "github.com/sigstore/cosign/v2/pkg/cosign/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/mutate"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	"github.com/sigstore/cosign/v2/pkg/oci/static"
+	cosignSig "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+	"github.com/sigstore/sigstore/pkg/signature/options"
+
+	"github.com/enterprise-contract/ec-cli/internal/utils/oci"
+)
+
+const (
+	// PredicateType of the SLSA Verification Summary Attestation
+	PredicateType = "https://slsa.dev/verification_summary/v1"
+
+	// VerifierID identifies ec as the verifier in the attestation
+	VerifierID = "https://enterprisecontract.dev/ec-cli"
+
+	// Passed and Failed are the possible verification results
+	Passed = "PASSED"
+	Failed = "FAILED"
+
+	slsaVersion = "1.0"
+)
+
+// Predicate of the SLSA Verification Summary Attestation
+type Predicate struct {
+	Verifier           Verifier           `json:"verifier"`
+	TimeVerified       time.Time          `json:"timeVerified"`
+	ResourceURI        string             `json:"resourceUri"`
+	Policy             ResourceDescriptor `json:"policy"`
+	VerificationResult string             `json:"verificationResult"`
+	VerifiedLevels     []string           `json:"verifiedLevels"`
+	SlsaVersion        string             `json:"slsaVersion"`
+}
+
+type Verifier struct {
+	ID      string            `json:"id"`
+	Version map[string]string `json:"version,omitempty"`
+}
+
+type ResourceDescriptor struct {
+	URI         string            `json:"uri,omitempty"`
+	Digest      map[string]string `json:"digest,omitempty"`
+	Annotations map[string]any    `json:"annotations,omitempty"`
+}
+
+// Statement is the in-toto statement holding the Verification Summary
+// Attestation
+type Statement struct {
+	in_toto.StatementHeader
+	Predicate Predicate `json:"predicate"`
+}
+
+// NewStatement returns the statement for the image with the given digest
+// reference and the predicate, the SLSA version of the predicate is set.
+func NewStatement(ref name.Digest, predicate Predicate) Statement {
+	algorithm, hex, _ := strings.Cut(ref.DigestStr(), ":")
+	predicate.SlsaVersion = slsaVersion
+
+	return Statement{
+		StatementHeader: in_toto.StatementHeader{
+			Type:          in_toto.StatementInTotoV01,
+			PredicateType: PredicateType,
+			Subject: []in_toto.Subject{
+				{
+					Name:   ref.Context().Name(),
+					Digest: map[string]string{algorithm: hex},
+				},
+			},
+		},
+		Predicate: predicate,
+	}
+}
+
+// Signer signs Verification Summary Attestations with a private key.
+type Signer struct {
+	signer signature.SignerVerifier
+}
+
+// NewSigner returns a Signer using the key at the given reference, i.e. a
+// file path or a reference supported by cosign, e.g. k8s://namespace/name.
+// The password of the key, if any, is read from the COSIGN_PASSWORD
+// environment variable.
+func NewSigner(ctx context.Context, keyRef string) (*Signer, error) {
+	sv, err := cosignSig.SignerVerifierFromKeyRef(ctx, keyRef, oci.PassFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signer{signer: sv}, nil
+}
+
+// Sign returns the DSSE envelope holding the signed statement.
+func (s *Signer) Sign(ctx context.Context, statement Statement) ([]byte, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapped := dsse.WrapSigner(s.signer, types.IntotoPayloadType)
+
+	return wrapped.SignMessage(bytes.NewReader(payload), options.WithContext(ctx))
+}
+
+// Attach pushes the signed envelope to the registry as a cosign attestation
+// of the image with the given digest reference, replacing any previous
+// Verification Summary Attestation of the image.
+func Attach(ctx context.Context, ref name.Digest, envelope []byte, opts ...remote.Option) error {
+	att, err := static.NewAttestation(envelope,
+		static.WithLayerMediaType(types.DssePayloadType),
+		static.WithAnnotations(map[string]string{"predicateType": PredicateType}))
+	if err != nil {
+		return err
+	}
+
+	ociOpts := []ociremote.Option{ociremote.WithRemoteOptions(oci.RemoteOptions(ctx, opts...)...)}
+
+	se, err := ociremote.SignedEntity(ref, ociOpts...)
+	if err != nil {
+		return err
+	}
+
+	se, err = mutate.AttachAttestationToEntity(se, att, mutate.WithReplaceOp(cremote.NewReplaceOp(PredicateType)))
+	if err != nil {
+		return err
+	}
+
+	return ociremote.WriteAttestations(ref.Repository, se, ociOpts...)
+}
@@ -0,0 +1,169 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package vsa
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	ociremote "github.com/sigstore/cosign/v2/pkg/oci/remote"
+	cosignSig "github.com/sigstore/cosign/v2/pkg/signature"
+	"github.com/sigstore/cosign/v2/pkg/types"
+	"github.com/sigstore/sigstore/pkg/signature/dsse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const digest = "sha256:4e388ab32b10dc8dbc7e28144f552830adc74787c1e2c0824032078a79f227fb"
+
+func keyPair(t *testing.T) (privateKey string, publicKey []byte) {
+	keys, err := cosign.GenerateKeyPair(func(bool) ([]byte, error) { return nil, nil })
+	require.NoError(t, err)
+
+	privateKey = path.Join(t.TempDir(), "cosign.key")
+	require.NoError(t, os.WriteFile(privateKey, keys.PrivateBytes, 0o600))
+
+	return privateKey, keys.PublicBytes
+}
+
+func predicate() Predicate {
+	return Predicate{
+		Verifier:           Verifier{ID: VerifierID, Version: map[string]string{"ec": "v1.2.3"}},
+		TimeVerified:       time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC),
+		ResourceURI:        "registry.io/repository/image@" + digest,
+		Policy:             ResourceDescriptor{URI: "github.com/org/policy//policy.yaml"},
+		VerificationResult: Passed,
+		VerifiedLevels:     []string{"SLSA_BUILD_LEVEL_3"},
+	}
+}
+
+func TestNewStatement(t *testing.T) {
+	ref := name.MustParseReference("registry.io/repository/image:tag@" + digest).(name.Digest)
+
+	statement := NewStatement(ref, predicate())
+
+	assert.Equal(t, in_toto.StatementInTotoV01, statement.Type)
+	assert.Equal(t, PredicateType, statement.PredicateType)
+	assert.Equal(t, []in_toto.Subject{
+		{
+			Name:   "registry.io/repository/image",
+			Digest: map[string]string{"sha256": "4e388ab32b10dc8dbc7e28144f552830adc74787c1e2c0824032078a79f227fb"},
+		},
+	}, statement.Subject)
+	assert.Equal(t, "1.0", statement.Predicate.SlsaVersion)
+}
+
+func TestSign(t *testing.T) {
+	ctx := context.Background()
+	privateKey, publicKey := keyPair(t)
+
+	signer, err := NewSigner(ctx, privateKey)
+	require.NoError(t, err)
+
+	ref := name.MustParseReference("registry.io/repository/image@" + digest).(name.Digest)
+	statement := NewStatement(ref, predicate())
+
+	envelope, err := signer.Sign(ctx, statement)
+	require.NoError(t, err)
+
+	verifier, err := cosignSig.LoadPublicKeyRaw(publicKey, crypto.SHA256)
+	require.NoError(t, err)
+	require.NoError(t, dsse.WrapVerifier(verifier).VerifySignature(bytes.NewReader(envelope), nil))
+
+	var env struct {
+		PayloadType string `json:"payloadType"`
+		Payload     string `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(envelope, &env))
+	assert.Equal(t, types.IntotoPayloadType, env.PayloadType)
+
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	require.NoError(t, err)
+
+	var signed Statement
+	require.NoError(t, json.Unmarshal(payload, &signed))
+	assert.Equal(t, statement, signed)
+}
+
+func TestNewSignerMissingKey(t *testing.T) {
+	_, err := NewSigner(context.Background(), path.Join(t.TempDir(), "missing.key"))
+	assert.Error(t, err)
+}
+
+func TestAttach(t *testing.T) {
+	ctx := context.Background()
+
+	server := httptest.NewServer(registry.New())
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	img, err := random.Image(128, 1)
+	require.NoError(t, err)
+	imgDigest, err := img.Digest()
+	require.NoError(t, err)
+
+	tag := name.MustParseReference(fmt.Sprintf("%s/repository/image:latest", u.Host))
+	require.NoError(t, remote.Write(tag, img))
+
+	ref := tag.Context().Digest(imgDigest.String())
+
+	privateKey, _ := keyPair(t)
+	signer, err := NewSigner(ctx, privateKey)
+	require.NoError(t, err)
+
+	// Attaching twice replaces the first VSA
+	for i := 0; i < 2; i++ {
+		envelope, err := signer.Sign(ctx, NewStatement(ref, predicate()))
+		require.NoError(t, err)
+		require.NoError(t, Attach(ctx, ref, envelope))
+	}
+
+	si, err := ociremote.SignedImage(ref)
+	require.NoError(t, err)
+	atts, err := si.Attestations()
+	require.NoError(t, err)
+	sigs, err := atts.Get()
+	require.NoError(t, err)
+	require.Len(t, sigs, 1)
+
+	annotations, err := sigs[0].Annotations()
+	require.NoError(t, err)
+	assert.Equal(t, PredicateType, annotations["predicateType"])
+
+	mediaType, err := sigs[0].MediaType()
+	require.NoError(t, err)
+	assert.Equal(t, types.DssePayloadType, string(mediaType))
+}
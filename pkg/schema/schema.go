@@ -25,9 +25,14 @@ import (
 
 var SLSA_Provenance_v0_2 jsonschema.Schema
 
+var SLSA_Provenance_v1 jsonschema.Schema
+
 //go:embed slsa_provenance_v0.2.json
 var slsa_provenance_v0_2_json string
 
+//go:embed slsa_provenance_v1.json
+var slsa_provenance_v1_json string
+
 func init() {
 	jsonschema.RegisterKeyword("uniqueKeys", newUniqueKeys)
 
@@ -36,4 +41,8 @@ func init() {
 	if err := json.Unmarshal([]byte(slsa_provenance_v0_2_json), &SLSA_Provenance_v0_2); err != nil {
 		panic(err)
 	}
+
+	if err := json.Unmarshal([]byte(slsa_provenance_v1_json), &SLSA_Provenance_v1); err != nil {
+		panic(err)
+	}
 }
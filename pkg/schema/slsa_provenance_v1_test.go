@@ -0,0 +1,142 @@
+// Copyright 2023 Red Hat, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build unit
+
+package schema
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var validV1 = []byte(`{
+  "_type": "https://in-toto.io/Statement/v1",
+  "subject": [
+    {
+      "name": "subject_name",
+      "digest": {
+        "sha256": "abcdef0123456789"
+      }
+    }
+  ],
+  "predicateType": "https://slsa.dev/provenance/v1",
+  "predicate": {
+    "buildDefinition": {
+      "buildType": "https://tekton.dev/chains/v2/slsa",
+      "externalParameters": {
+        "runSpec": {}
+      },
+      "resolvedDependencies": [
+        {
+          "uri": "git+https://github.com/octocat/hello-world@refs/heads/main",
+          "digest": {
+            "sha1": "c27d339ee6075c1f744c5d4b200f7901aad2c369"
+          }
+        }
+      ]
+    },
+    "runDetails": {
+      "builder": {
+        "id": "https://tekton.dev/chains/v2"
+      },
+      "metadata": {
+        "invocationID": "abc",
+        "startedOn": "2023-05-01T10:00:00Z",
+        "finishedOn": "2023-05-01T10:05:00Z"
+      }
+    }
+  }
+}`)
+
+func checkV1(t *testing.T, valid bool, patches ...string) {
+	for i, patch := range patches {
+		t.Run(fmt.Sprintf("case_%d", i), func(t *testing.T) {
+			j, err := jsonpatch.MergePatch(validV1, []byte(patch))
+			require.NoError(t, err)
+
+			errs, err := SLSA_Provenance_v1.ValidateBytes(context.Background(), j)
+			require.NoError(t, err)
+
+			if valid {
+				assert.Empty(t, errs)
+			} else {
+				assert.NotEmpty(t, errs)
+			}
+		})
+	}
+}
+
+func TestV1Valid(t *testing.T) {
+	checkV1(t, true,
+		`{}`,
+		`{"_type": "https://in-toto.io/Statement/v0.1"}`,
+		`{"predicate": {"runDetails": {"metadata": null}}}`,
+		`{"predicate": {"buildDefinition": {"resolvedDependencies": null}}}`,
+		`{"predicate": {"runDetails": {"byproducts": [{"name": "log", "content": "aGVsbG8="}]}}}`,
+	)
+}
+
+func TestV1Type(t *testing.T) {
+	checkV1(t, false,
+		`{"_type": null}`,
+		`{"_type": ""}`,
+		`{"_type": "something else"}`,
+	)
+}
+
+func TestV1Subject(t *testing.T) {
+	checkV1(t, false,
+		`{"subject": null}`,
+		`{"subject": []}`,
+		`{"subject": [{"name": "a", "digest": {"foo": "abcdef0123456789"}}]}`,
+		`{"subject": [{"name": "x", "digest": {"sha256": "abcdef"}}, {"name": "x", "digest": {"sha256": "fedcba"}}]}`,
+	)
+}
+
+func TestV1PredicateType(t *testing.T) {
+	checkV1(t, false,
+		`{"predicateType": null}`,
+		`{"predicateType": "https://slsa.dev/provenance/v0.2"}`,
+	)
+}
+
+func TestV1BuildDefinition(t *testing.T) {
+	checkV1(t, false,
+		`{"predicate": {"buildDefinition": null}}`,
+		`{"predicate": {"buildDefinition": {"buildType": null}}}`,
+		`{"predicate": {"buildDefinition": {"buildType": "not_uri"}}}`,
+		`{"predicate": {"buildDefinition": {"externalParameters": null}}}`,
+		`{"predicate": {"buildDefinition": {"externalParameters": 1}}}`,
+		`{"predicate": {"buildDefinition": {"resolvedDependencies": [{}]}}}`,
+		`{"predicate": {"buildDefinition": {"resolvedDependencies": [{"digest": {"sha256": "g%-A"}}]}}}`,
+	)
+}
+
+func TestV1RunDetails(t *testing.T) {
+	checkV1(t, false,
+		`{"predicate": {"runDetails": null}}`,
+		`{"predicate": {"runDetails": {"builder": null}}}`,
+		`{"predicate": {"runDetails": {"builder": {"id": "not_uri"}}}}`,
+		`{"predicate": {"runDetails": {"metadata": {"invocationID": ""}}}}`,
+		`{"predicate": {"runDetails": {"metadata": {"finishedOn": "2023-05-01T10:05:00+01:00"}}}}`,
+	)
+}